@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+	"kv-ttl/kv"
+	"kv-ttl/pb"
+	"kv-ttl/server"
+	"net"
+	"testing"
+)
+
+// startServer serves the cache in process and returns the client connected to it
+// and the function stopping the server and closing the cache.
+func startServer(t *testing.T, cache kv.Cache) (pb.StorageClient, func()) {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	pb.RegisterStorageServer(srv, server.NewCacheServer(cache))
+	go srv.Serve(listener)
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := func() {
+		conn.Close()
+		srv.Stop()
+		cache.Close(context.Background())
+	}
+	return pb.NewStorageClient(conn), stop
+}
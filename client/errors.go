@@ -0,0 +1,124 @@
+// Package client provides helpers for Go applications that use the cache gRPC API.
+package client
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"kv-ttl/pb"
+)
+
+// IsNotFound reports whether the call failed because the key is absent or expired.
+func IsNotFound(err error) bool {
+	return status.Code(err) == codes.NotFound && !IsNamespaceNotFound(err)
+}
+
+// IsExists reports whether the call failed because the key is already in the cache.
+func IsExists(err error) bool {
+	return status.Code(err) == codes.AlreadyExists && !IsNamespaceExists(err)
+}
+
+// IsVersionMismatch reports whether a compare-and-swap failed because the key has another version.
+func IsVersionMismatch(err error) bool {
+	return hasReason(err, pb.ReasonVersionMismatch)
+}
+
+// IsNotNumeric reports whether an increment failed because the value is not an integer.
+func IsNotNumeric(err error) bool {
+	return hasReason(err, pb.ReasonNotNumeric)
+}
+
+// IsOverflow reports whether an increment failed because the result doesn't fit into int64.
+func IsOverflow(err error) bool {
+	return hasReason(err, pb.ReasonOverflow)
+}
+
+// IsLocked reports whether a lock was not acquired because it is held by another owner.
+func IsLocked(err error) bool {
+	return hasReason(err, pb.ReasonLocked)
+}
+
+// IsNotOwner reports whether a lock was not renewed or released because it is held by another owner.
+func IsNotOwner(err error) bool {
+	return hasReason(err, pb.ReasonNotOwner)
+}
+
+// IsInvalidTtl reports whether the call was rejected because of the given TTL or expiration date.
+func IsInvalidTtl(err error) bool {
+	return hasReason(err, pb.ReasonInvalidTtl)
+}
+
+// IsTooLarge reports whether a write was rejected because the value can never fit into the cache.
+func IsTooLarge(err error) bool {
+	return hasReason(err, pb.ReasonTooLarge)
+}
+
+// IsInvalidKey reports whether a write was rejected because the key is empty.
+func IsInvalidKey(err error) bool {
+	return hasReason(err, pb.ReasonInvalidKey)
+}
+
+// IsNamespaceNotFound reports whether the call failed because the namespace doesn't exist.
+func IsNamespaceNotFound(err error) bool {
+	return hasReason(err, pb.ReasonNamespaceNotFound)
+}
+
+// IsNamespaceExists reports whether a namespace wasn't created because it already exists.
+func IsNamespaceExists(err error) bool {
+	return hasReason(err, pb.ReasonNamespaceExists)
+}
+
+// IsReadOnly reports whether a write was rejected because the server is a replication follower.
+func IsReadOnly(err error) bool {
+	return hasReason(err, pb.ReasonReadOnly)
+}
+
+// IsUnavailable reports whether a write wasn't made because the storage of the server failed to record it.
+// Retrying the write later may succeed.
+func IsUnavailable(err error) bool {
+	return hasReason(err, pb.ReasonUnavailable)
+}
+
+// ErrorLeader returns the address of the leader to send the write rejected by a follower to.
+func ErrorLeader(err error) (string, bool) {
+	info, ok := errorInfo(err, pb.ReasonReadOnly)
+	if !ok {
+		return "", false
+	}
+	leader, ok := info.Metadata["leader"]
+	return leader, ok
+}
+
+// ErrorKey returns the key the error is related to if the server attached it.
+func ErrorKey(err error) (string, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return "", false
+	}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ResourceInfo); ok && info.ResourceType == pb.ResourceType {
+			return info.ResourceName, true
+		}
+	}
+	return "", false
+}
+
+// hasReason reports whether the error carries ErrorInfo details with the given reason.
+func hasReason(err error, reason string) bool {
+	_, ok := errorInfo(err, reason)
+	return ok
+}
+
+// errorInfo returns the ErrorInfo details of the error with the given reason.
+func errorInfo(err error, reason string) (*errdetails.ErrorInfo, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil, false
+	}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.Domain == pb.ErrorDomain && info.Reason == reason {
+			return info, true
+		}
+	}
+	return nil, false
+}
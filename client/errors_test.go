@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"github.com/golang/protobuf/ptypes"
+	"kv-ttl/kv"
+	"kv-ttl/pb"
+	"testing"
+)
+
+func TestErrorHelpers(t *testing.T) {
+	ctx := context.Background()
+	c, stop := startServer(t, kv.NewCache(kv.Configuration{}))
+	defer stop()
+	value := &pb.T{Value: []byte("v")}
+
+	_, err := c.AddWithTtl(ctx, &pb.KeyValueTtl{Key: "k", Value: value, Ttl: ptypes.DurationProto(-1)})
+	if !IsInvalidTtl(err) {
+		t.Errorf("expected an invalid ttl, got %v", err)
+	}
+	_, err = c.Add(ctx, &pb.KeyValue{Key: "", Value: value})
+	if !IsInvalidKey(err) || IsInvalidTtl(err) {
+		t.Errorf("expected an invalid key, got %v", err)
+	}
+	c.Add(ctx, &pb.KeyValue{Key: "k", Value: value})
+	_, err = c.CompareAndSwap(ctx, &pb.CasRequest{Key: "k", Value: value})
+	if !IsExists(err) || IsVersionMismatch(err) {
+		t.Errorf("expected the key to exist, got %v", err)
+	}
+	_, err = c.Set(ctx, &pb.SetRequest{Key: "k", Value: value, Mode: 7})
+	if err == nil || IsInvalidTtl(err) {
+		t.Errorf("expected an unknown mode not to be an invalid ttl, got %v", err)
+	}
+	_, err = c.Scan(ctx, &pb.ScanRequest{Count: 5000})
+	if err == nil || IsInvalidTtl(err) {
+		t.Errorf("expected a wrong count not to be an invalid ttl, got %v", err)
+	}
+}
@@ -1,4 +1,4 @@
-// The client demonstrates how to connect to grpc server and call the cache methods
+// The example demonstrates how to connect to grpc server and call the cache methods
 package main
 
 import (
@@ -7,7 +7,7 @@ import (
 	"github.com/golang/protobuf/ptypes"
 	"google.golang.org/grpc"
 	"io"
-	"kv-ttl/kv"
+	"kv-ttl/client"
 	"kv-ttl/pb"
 	"log"
 	"time"
@@ -50,7 +50,10 @@ func main() {
 	assertedPrint("Five", resp, err)
 
 	resp, err = cl.Value(ctx, pbk("6"))
-	assertedPrint("#6 error not_found", resp, err)
+	assertedPrint("#6 error not found", resp, err)
+	if key, ok := client.ErrorKey(err); ok && client.IsNotFound(err) {
+		fmt.Printf("key %q is not in the cache\n", key)
+	}
 
 	// Wait until ttl is ended but wasn't swept yet
 	time.Sleep(3600 * ms)
@@ -97,12 +100,12 @@ func assertedPrint(expected string, v interface{}, err error) {
 // printAll calls ListAll method and prints all the values
 func printAll(cl pb.StorageClient) {
 	ctx := context.Background()
-	stream, err := cl.ListAll(ctx, &pb.Empty{})
+	stream, err := cl.ListAll(ctx, &pb.Namespace{})
 	if err != nil {
 		log.Println(err)
 		return
 	}
-	var values []kv.T
+	var values []string
 	for {
 		value, err := stream.Recv()
 		if err == io.EOF {
@@ -111,7 +114,7 @@ func printAll(cl pb.StorageClient) {
 		if err != nil {
 			log.Fatal(err)
 		}
-		values = append(values, kv.T{V: value.Value})
+		values = append(values, string(value.Value))
 	}
 	fmt.Printf("#all: %v\n", values)
 }
@@ -123,5 +126,5 @@ func pbk(key string) *pb.Key {
 }
 
 func pbt(value string) *pb.T {
-	return &pb.T{Value: value}
+	return &pb.T{Value: []byte(value), ContentType: "text/plain"}
 }
@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/protobuf/ptypes"
+	"kv-ttl/pb"
+	"sync"
+	"time"
+)
+
+// ErrLockLost is returned by Lock.Err when the lock couldn't be renewed in time.
+// The lock is declared lost while a third of Ttl is left, before the server lets it expire.
+var ErrLockLost = errors.New("lock was not renewed in time")
+
+// ErrLockTtlTooShort is returned by AcquireLock when the Ttl is below MinLockTtl.
+var ErrLockTtlTooShort = errors.New("lock ttl is too short to renew the lock")
+
+// MinLockTtl is the shortest Ttl of a lock, it leaves a third of it for every renewal call.
+const MinLockTtl = 30 * time.Millisecond
+
+// LockOptions describes the lock to acquire.
+type LockOptions struct {
+	Namespace string
+	Name      string
+	Owner     string
+	// Ttl is how long the lock is held without renewal, it is renewed every third of Ttl.
+	Ttl time.Duration
+}
+
+// Lock is held by the owner and renewed in the background until it is released or lost.
+type Lock struct {
+	// Token is the fencing token of the lock, pass it to the resources guarded by the lock
+	// so they can reject the requests of the previous holders.
+	Token uint64
+
+	client   pb.StorageClient
+	opts     LockOptions
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+	lost     chan struct{}
+	err      error
+}
+
+// AcquireLock takes the lock and starts renewing it.
+// Use IsLocked to check whether the lock is held by another owner.
+func AcquireLock(ctx context.Context, c pb.StorageClient, opts LockOptions) (*Lock, error) {
+	if opts.Ttl < MinLockTtl {
+		return nil, ErrLockTtlTooShort
+	}
+	l := &Lock{
+		client: c,
+		opts:   opts,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+		lost:   make(chan struct{}),
+	}
+	start := time.Now()
+	resp, err := c.Lock(ctx, l.request())
+	if err != nil {
+		return nil, err
+	}
+	l.Token = resp.Token
+	go l.renew(start)
+	return l, nil
+}
+
+func (l *Lock) request() *pb.LockRequest {
+	return &pb.LockRequest{
+		Namespace: l.opts.Namespace,
+		Name:      l.opts.Name,
+		Owner:     l.opts.Owner,
+		Token:     l.Token,
+		Ttl:       ptypes.DurationProto(l.opts.Ttl),
+	}
+}
+
+// renew extends the lock until it is stopped. Failed renewals are retried
+// until the server reports that the lock is not held or only a third of the ttl is left
+// since the start of the last successful call, the server counts the ttl from a later moment.
+func (l *Lock) renew(renewed time.Time) {
+	defer close(l.done)
+	interval := l.opts.Ttl / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+		start := time.Now()
+		lostAt := renewed.Add(l.opts.Ttl - interval)
+		if !start.Before(lostAt) {
+			l.err = ErrLockLost
+			close(l.lost)
+			return
+		}
+		deadline := start.Add(interval)
+		if lostAt.Before(deadline) {
+			deadline = lostAt
+		}
+		ctx, cancel := context.WithDeadline(context.Background(), deadline)
+		_, err := l.client.Renew(ctx, l.request())
+		cancel()
+		switch {
+		case err == nil:
+			renewed = start
+		case IsNotFound(err) || IsNotOwner(err):
+			l.err = err
+		case !time.Now().Before(lostAt):
+			l.err = ErrLockLost
+		}
+		if l.err != nil {
+			close(l.lost)
+			return
+		}
+	}
+}
+
+// Lost is closed when the lock couldn't be renewed and may be held by another owner.
+func (l *Lock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Err returns the reason why the lock was lost, nil while the lock is held.
+func (l *Lock) Err() error {
+	select {
+	case <-l.lost:
+		return l.err
+	default:
+		return nil
+	}
+}
+
+// Release stops the renewal and releases the lock unless it has been lost.
+func (l *Lock) Release(ctx context.Context) error {
+	l.stopOnce.Do(func() { close(l.stop) })
+	<-l.done
+	if err := l.Err(); err != nil {
+		return err
+	}
+	_, err := l.client.Unlock(ctx, l.request())
+	return err
+}
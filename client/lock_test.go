@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"kv-ttl/kv"
+	"kv-ttl/pb"
+	"testing"
+	"time"
+)
+
+func TestLockRenewal(t *testing.T) {
+	cache := kv.NewCache(kv.Configuration{})
+	c, stop := startServer(t, cache)
+	defer stop()
+	ctx := context.Background()
+	opts := LockOptions{Name: "leader", Owner: "a", Ttl: 300 * time.Millisecond}
+
+	a, err := AcquireLock(ctx, c, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * opts.Ttl)
+	other := opts
+	other.Owner = "b"
+	if _, err := AcquireLock(ctx, c, other); !IsLocked(err) {
+		t.Fatalf("expected the renewed lock to be held, got %v", err)
+	}
+	if err := a.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := AcquireLock(ctx, c, other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Token <= a.Token {
+		t.Errorf("expected a greater fencing token, got %d after %d", b.Token, a.Token)
+	}
+	cache.Remove("leader")
+	select {
+	case <-b.Lost():
+	case <-time.After(2 * opts.Ttl):
+		t.Fatal("expected the lock to be lost")
+	}
+	if !IsNotFound(b.Err()) {
+		t.Errorf("expected not found, got %v", b.Err())
+	}
+	if err := b.Release(ctx); err == nil {
+		t.Error("expected release of a lost lock to fail")
+	}
+}
+
+// unreachable fails the renewals as if the server couldn't be reached.
+type unreachable struct {
+	pb.StorageClient
+}
+
+func (unreachable) Renew(context.Context, *pb.LockRequest, ...grpc.CallOption) (*pb.Empty, error) {
+	return nil, status.Error(codes.Unavailable, "unreachable")
+}
+
+// The lock that cannot be renewed is lost before the server lets it expire.
+func TestLockLostBeforeExpiration(t *testing.T) {
+	cache := kv.NewCache(kv.Configuration{})
+	c, stop := startServer(t, cache)
+	defer stop()
+	ttl := 300 * time.Millisecond
+	start := time.Now()
+	l, err := AcquireLock(context.Background(), unreachable{c}, LockOptions{Name: "l", Owner: "a", Ttl: ttl})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-l.Lost():
+	case <-time.After(ttl):
+		t.Fatal("expected the lock to be lost before it expires")
+	}
+	if elapsed := time.Since(start); elapsed >= ttl-ttl/6 {
+		t.Errorf("expected the lock to be lost with a third of the ttl left, took %v", elapsed)
+	}
+	if l.Err() != ErrLockLost {
+		t.Errorf("expected ErrLockLost, got %v", l.Err())
+	}
+	if _, ok := cache.Value("l"); !ok {
+		t.Error("expected the lock to be still held on the server")
+	}
+}
+
+func TestLockTtlTooShort(t *testing.T) {
+	cache := kv.NewCache(kv.Configuration{})
+	c, stop := startServer(t, cache)
+	defer stop()
+	for _, ttl := range []time.Duration{0, time.Nanosecond, MinLockTtl - 1} {
+		if _, err := AcquireLock(context.Background(), c, LockOptions{Name: "l", Owner: "a", Ttl: ttl}); err != ErrLockTtlTooShort {
+			t.Errorf("expected ErrLockTtlTooShort for %v, got %v", ttl, err)
+		}
+	}
+	if _, ok := cache.Value("l"); ok {
+		t.Error("expected the lock not to be taken")
+	}
+}
@@ -0,0 +1,338 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"io"
+	"kv-ttl/pb"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// DefaultVirtualNodes is the number of points every node has on the hash ring.
+const DefaultVirtualNodes = 128
+
+// defaultScanCount is the page size used by the servers when the count is not set.
+const defaultScanCount = 100
+
+// ErrNoNodes is returned by ShardedClient when it has no nodes to send the call to.
+var ErrNoNodes = errors.New("sharded client has no nodes")
+
+// point is a virtual node on the hash ring.
+type point struct {
+	hash uint32
+	node string
+}
+
+// ShardedClient spreads the keys over several servers by consistent hashing.
+// Every node owns many virtual nodes on the ring, so the keys are spread evenly
+// and adding or removing a node moves only the keys it gains or loses.
+// Calls involving several keys or the whole data are sent to the nodes in parallel.
+type ShardedClient struct {
+	virtualNodes int
+
+	mu    sync.RWMutex
+	nodes map[string]pb.StorageClient
+	ring  []point
+}
+
+// NewShardedClient creates the client routing the keys to the nodes by their names,
+// e.g. addresses. DefaultVirtualNodes is used if virtualNodes is not positive.
+func NewShardedClient(nodes map[string]pb.StorageClient, virtualNodes int) *ShardedClient {
+	if virtualNodes <= 0 {
+		virtualNodes = DefaultVirtualNodes
+	}
+	s := &ShardedClient{virtualNodes: virtualNodes, nodes: make(map[string]pb.StorageClient)}
+	for name, c := range nodes {
+		s.nodes[name] = c
+	}
+	s.build()
+	return s
+}
+
+// build recreates the ring from the nodes. Must be called with the lock held.
+func (s *ShardedClient) build() {
+	s.ring = make([]point, 0, len(s.nodes)*s.virtualNodes)
+	for name := range s.nodes {
+		for i := 0; i < s.virtualNodes; i++ {
+			s.ring = append(s.ring, point{hash: hash(name + "#" + strconv.Itoa(i)), node: name})
+		}
+	}
+	sort.Slice(s.ring, func(i, j int) bool {
+		if s.ring[i].hash != s.ring[j].hash {
+			return s.ring[i].hash < s.ring[j].hash
+		}
+		return s.ring[i].node < s.ring[j].node
+	})
+}
+
+func hash(s string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return h.Sum32()
+}
+
+// AddNode adds the node or replaces the connection of the node with the same name.
+// Keys are not moved between the servers, the ones now owned by the new node are not found until written again.
+func (s *ShardedClient) AddNode(name string, c pb.StorageClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, exists := s.nodes[name]
+	s.nodes[name] = c
+	if !exists {
+		s.build()
+	}
+}
+
+// RemoveNode removes the node, its keys are owned by the remaining nodes from now on.
+func (s *ShardedClient) RemoveNode(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.nodes[name]; ok {
+		delete(s.nodes, name)
+		s.build()
+	}
+}
+
+// Nodes returns the sorted names of the nodes.
+func (s *ShardedClient) Nodes() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.nodes))
+	for name := range s.nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NodeFor returns the name of the node owning the key, empty if there are no nodes.
+func (s *ShardedClient) NodeFor(key string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.owner(key)
+}
+
+// owner finds the first virtual node following the hash of the key. Must be called with the lock held.
+func (s *ShardedClient) owner(key string) string {
+	if len(s.ring) == 0 {
+		return ""
+	}
+	h := hash(key)
+	i := sort.Search(len(s.ring), func(i int) bool { return s.ring[i].hash >= h })
+	if i == len(s.ring) {
+		i = 0
+	}
+	return s.ring[i].node
+}
+
+// Client returns the connection to the node owning the key, use it for the calls with a single key.
+func (s *ShardedClient) Client(key string) (pb.StorageClient, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.nodes) == 0 {
+		return nil, ErrNoNodes
+	}
+	return s.nodes[s.owner(key)], nil
+}
+
+// fanOut calls fn for every node in parallel and returns the first error.
+func fanOut(nodes map[string]pb.StorageClient, fn func(name string, c pb.StorageClient) error) error {
+	if len(nodes) == 0 {
+		return ErrNoNodes
+	}
+	errs := make(chan error, len(nodes))
+	for name, c := range nodes {
+		go func(name string, c pb.StorageClient) {
+			errs <- fn(name, c)
+		}(name, c)
+	}
+	var err error
+	for range nodes {
+		if nErr := <-errs; err == nil {
+			err = nErr
+		}
+	}
+	return err
+}
+
+// snapshot returns the current nodes so that the calls are not affected by concurrent changes.
+func (s *ShardedClient) snapshot() map[string]pb.StorageClient {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	nodes := make(map[string]pb.StorageClient, len(s.nodes))
+	for name, c := range s.nodes {
+		nodes[name] = c
+	}
+	return nodes
+}
+
+// group splits the positions of the keys by the nodes owning them.
+func (s *ShardedClient) group(keys []string) (map[string]pb.StorageClient, map[string][]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.nodes) == 0 {
+		return nil, nil, ErrNoNodes
+	}
+	nodes := make(map[string]pb.StorageClient)
+	positions := make(map[string][]int)
+	for i, k := range keys {
+		name := s.owner(k)
+		nodes[name] = s.nodes[name]
+		positions[name] = append(positions[name], i)
+	}
+	return nodes, positions, nil
+}
+
+// ListAll returns the values of the namespace from all the nodes.
+func (s *ShardedClient) ListAll(ctx context.Context, req *pb.Namespace) ([]*pb.T, error) {
+	var mu sync.Mutex
+	values := make([]*pb.T, 0)
+	err := fanOut(s.snapshot(), func(_ string, c pb.StorageClient) error {
+		stream, err := c.ListAll(ctx, req)
+		if err != nil {
+			return err
+		}
+		for {
+			v, err := stream.Recv()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			values = append(values, v)
+			mu.Unlock()
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Scan returns a page of the items of all the nodes ordered by key.
+// The cursor is the last key of the page like the one of a single server.
+func (s *ShardedClient) Scan(ctx context.Context, req *pb.ScanRequest) (*pb.ScanResponse, error) {
+	count := int(req.Count)
+	if count <= 0 {
+		count = defaultScanCount
+	}
+	var mu sync.Mutex
+	resp := &pb.ScanResponse{Items: make([]*pb.Item, 0)}
+	more := false
+	err := fanOut(s.snapshot(), func(_ string, c pb.StorageClient) error {
+		page, err := c.Scan(ctx, req)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		resp.Items = append(resp.Items, page.Items...)
+		more = more || page.NextCursor != ""
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Every node returns its first keys after the cursor, so the first keys of the merged
+	// pages are the first keys of all the data and nothing is skipped by the next cursor.
+	sort.Slice(resp.Items, func(i, j int) bool { return resp.Items[i].Key < resp.Items[j].Key })
+	if len(resp.Items) > count {
+		resp.Items = resp.Items[:count]
+		more = true
+	}
+	if more && len(resp.Items) > 0 {
+		resp.NextCursor = resp.Items[len(resp.Items)-1].Key
+	}
+	return resp, nil
+}
+
+// MultiGet reads the keys from the nodes owning them, the results are in the order of the keys.
+func (s *ShardedClient) MultiGet(ctx context.Context, req *pb.Keys) (*pb.MultiGetResponse, error) {
+	nodes, positions, err := s.group(req.Keys)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.MultiGetResponse{Results: make([]*pb.GetResult, len(req.Keys))}
+	err = fanOut(nodes, func(name string, c pb.StorageClient) error {
+		keys := make([]string, len(positions[name]))
+		for i, p := range positions[name] {
+			keys[i] = req.Keys[p]
+		}
+		part, err := c.MultiGet(ctx, &pb.Keys{Keys: keys, Namespace: req.Namespace})
+		if err != nil {
+			return err
+		}
+		for i, r := range part.Results {
+			resp.Results[positions[name][i]] = r
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// MultiSet writes the items to the nodes owning them, the results are in the order of the items.
+// The items are written by each node separately, a failed call may leave the items of the other nodes written.
+func (s *ShardedClient) MultiSet(ctx context.Context, req *pb.MultiSetRequest) (*pb.MultiSetResponse, error) {
+	keys := make([]string, len(req.Items))
+	for i, item := range req.Items {
+		keys[i] = item.Key
+	}
+	nodes, positions, err := s.group(keys)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.MultiSetResponse{Results: make([]*pb.SetResult, len(req.Items))}
+	err = fanOut(nodes, func(name string, c pb.StorageClient) error {
+		items := make([]*pb.SetRequest, len(positions[name]))
+		for i, p := range positions[name] {
+			items[i] = req.Items[p]
+		}
+		part, err := c.MultiSet(ctx, &pb.MultiSetRequest{Items: items, Namespace: req.Namespace})
+		if err != nil {
+			return err
+		}
+		for i, r := range part.Results {
+			resp.Results[positions[name][i]] = r
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// MultiDelete removes the keys from the nodes owning them, the results are in the order of the keys.
+func (s *ShardedClient) MultiDelete(ctx context.Context, req *pb.Keys) (*pb.MultiDeleteResponse, error) {
+	nodes, positions, err := s.group(req.Keys)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.MultiDeleteResponse{Results: make([]*pb.DeleteResult, len(req.Keys))}
+	err = fanOut(nodes, func(name string, c pb.StorageClient) error {
+		keys := make([]string, len(positions[name]))
+		for i, p := range positions[name] {
+			keys[i] = req.Keys[p]
+		}
+		part, err := c.MultiDelete(ctx, &pb.Keys{Keys: keys, Namespace: req.Namespace})
+		if err != nil {
+			return err
+		}
+		for i, r := range part.Results {
+			resp.Results[positions[name][i]] = r
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"kv-ttl/kv"
+	"kv-ttl/pb"
+	"testing"
+)
+
+func TestShardedClient(t *testing.T) {
+	ctx := context.Background()
+	caches := make(map[string]kv.Cache)
+	nodes := make(map[string]pb.StorageClient)
+	for _, name := range []string{"a", "b", "c"} {
+		caches[name] = kv.NewCache(kv.Configuration{})
+		c, stop := startServer(t, caches[name])
+		defer stop()
+		nodes[name] = c
+	}
+	s := NewShardedClient(nodes, 0)
+
+	keys := make([]string, 100)
+	items := make([]*pb.SetRequest, 0, len(keys))
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%03d", i)
+		items = append(items, &pb.SetRequest{Key: keys[i], Value: &pb.T{Value: []byte(keys[i])}})
+	}
+	if _, err := s.MultiSet(ctx, &pb.MultiSetRequest{Items: items[:50]}); err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range items[50:] {
+		c, err := s.Client(item.Key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.Set(ctx, item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, cache := range caches {
+		n := len(cache.ListAll())
+		if n == 0 || n == len(keys) {
+			t.Errorf("expected the keys to be spread, node %s has %d", name, n)
+		}
+		for _, k := range keys {
+			if _, ok := cache.Value(k); ok != (s.NodeFor(k) == name) {
+				t.Errorf("key %s is on the wrong node %s", k, name)
+			}
+		}
+	}
+
+	values, err := s.ListAll(ctx, &pb.Namespace{})
+	if err != nil || len(values) != len(keys) {
+		t.Errorf("expected all the values, got %d %v", len(values), err)
+	}
+	var scanned []string
+	req := &pb.ScanRequest{Count: 7, Prefix: "key"}
+	for {
+		page, err := s.Scan(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, item := range page.Items {
+			scanned = append(scanned, item.Key)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		req.Cursor = page.NextCursor
+	}
+	if fmt.Sprint(scanned) != fmt.Sprint(keys) {
+		t.Errorf("expected the keys in order, got %v", scanned)
+	}
+
+	got, err := s.MultiGet(ctx, &pb.Keys{Keys: []string{"key042", "missing", "key007"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Results) != 3 || string(got.Results[0].Value.Value) != "key042" || got.Results[1].Found || string(got.Results[2].Value.Value) != "key007" {
+		t.Errorf("unexpected results %v", got.Results)
+	}
+	deleted, err := s.MultiDelete(ctx, &pb.Keys{Keys: []string{"key001", "missing"}})
+	if err != nil || !deleted.Results[0].Deleted || deleted.Results[1].Deleted {
+		t.Errorf("unexpected results %v %v", deleted, err)
+	}
+}
+
+func TestShardedClientRemapping(t *testing.T) {
+	s := NewShardedClient(map[string]pb.StorageClient{"a": nil, "b": nil, "c": nil}, 0)
+	keys := make([]string, 10000)
+	before := make(map[string]string)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%d", i)
+		before[keys[i]] = s.NodeFor(keys[i])
+	}
+
+	// Only the keys taken by the new node move.
+	s.AddNode("d", nil)
+	moved := 0
+	for _, k := range keys {
+		if owner := s.NodeFor(k); owner != before[k] {
+			if owner != "d" {
+				t.Fatalf("key %s moved from %s to %s", k, before[k], owner)
+			}
+			moved++
+		}
+	}
+	if moved < len(keys)/8 || moved > len(keys)*3/8 {
+		t.Errorf("expected about a quarter of the keys to move, moved %d", moved)
+	}
+
+	// Removing the node returns its keys to the previous owners.
+	s.RemoveNode("d")
+	for _, k := range keys {
+		if owner := s.NodeFor(k); owner != before[k] {
+			t.Fatalf("expected key %s on %s, got %s", k, before[k], owner)
+		}
+	}
+	s.RemoveNode("b")
+	for _, k := range keys {
+		if owner := s.NodeFor(k); before[k] != "b" && owner != before[k] {
+			t.Fatalf("key %s moved from %s to %s", k, before[k], owner)
+		}
+	}
+	if _, err := NewShardedClient(nil, 0).Client("k"); err != ErrNoNodes {
+		t.Errorf("expected ErrNoNodes, got %v", err)
+	}
+}
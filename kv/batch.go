@@ -0,0 +1,114 @@
+package kv
+
+import "time"
+
+// GetResult is the result of MultiGet for a single key.
+type GetResult struct {
+	TtlBox
+	Found bool
+}
+
+// SetItem describes a single write of MultiSet, see Cache.Set for the meaning of the fields.
+type SetItem struct {
+	Key   string
+	Value T
+	Ttl   time.Duration
+	Mode  WriteMode
+}
+
+// MultiGet returns the entries for the keys in the same order.
+// Each shard is locked once for all its keys.
+func (c *cache) MultiGet(keys []string) []GetResult {
+	results := make([]GetResult, len(keys))
+	now := c.clock.Now()
+	for _, g := range c.groupByShard(len(keys), func(i int) string { return keys[i] }) {
+		s, indexes := g.shard, g.indexes
+		s.mu.RLock()
+		for _, i := range indexes {
+			box, ok := s.values[keys[i]]
+			if ok && !box.IsExpired(now) {
+				s.touch(keys[i])
+				results[i] = GetResult{TtlBox: box, Found: true}
+			}
+		}
+		s.mu.RUnlock()
+	}
+	return results
+}
+
+// MultiSet stores the items and reports whether each write happened in the same order.
+// Each shard is locked once for all its keys, the items with the same key are applied in order.
+// The items are checked before anything is written, a *KeyError is returned for
+// the first one that cannot be stored. If the storage fails to record a write, the error
+// is returned and the remaining items are not written, the results report the ones written before.
+func (c *cache) MultiSet(items []SetItem) ([]bool, error) {
+	written := make([]bool, len(items))
+	for _, item := range items {
+		if err := c.checkEntry(item.Key, TtlBox{Content: item.Value}); err != nil {
+			return written, &KeyError{Key: item.Key, Err: err}
+		}
+	}
+	now := c.clock.Now()
+	for _, g := range c.groupByShard(len(items), func(i int) string { return items[i].Key }) {
+		s, indexes := g.shard, g.indexes
+		s.mu.Lock()
+		for _, i := range indexes {
+			item := items[i]
+			expired := c.expiration(now, item.Ttl)
+			ok, err := c.addLocked(s, item.Key, item.Value, expired, 0, item.Mode, now)
+			if err != nil {
+				s.mu.Unlock()
+				return written, err
+			}
+			written[i] = ok
+		}
+		s.mu.Unlock()
+	}
+	return written, nil
+}
+
+// MultiDelete removes the keys and reports whether each of them was in the cache in the same order.
+// Each shard is locked once for all its keys. Storage failures are handled like by MultiSet.
+func (c *cache) MultiDelete(keys []string) ([]bool, error) {
+	deleted := make([]bool, len(keys))
+	now := c.clock.Now()
+	for _, g := range c.groupByShard(len(keys), func(i int) string { return keys[i] }) {
+		s, indexes := g.shard, g.indexes
+		s.mu.Lock()
+		for _, i := range indexes {
+			ok, err := c.removeLocked(s, keys[i], now)
+			if err != nil {
+				s.mu.Unlock()
+				return deleted, err
+			}
+			deleted[i] = ok
+		}
+		s.mu.Unlock()
+	}
+	return deleted, nil
+}
+
+// shardGroup holds the indexes of the keys of a shard.
+type shardGroup struct {
+	shard   *shard
+	indexes []int
+}
+
+// groupByShard returns indexes of n keys grouped by the shards responsible for them.
+// The groups are ordered by their first index and the indexes of every group are in ascending order,
+// so the earlier keys are handled first.
+func (c *cache) groupByShard(n int, key func(i int) string) []shardGroup {
+	groups := make([]shardGroup, 0)
+	positions := make(map[*shard]int)
+	for i := 0; i < n; i++ {
+		s := c.shardFor(key(i))
+		p, ok := positions[s]
+		if !ok {
+			p = len(groups)
+			positions[s] = p
+			groups = append(groups, shardGroup{shard: s})
+		}
+		groups[p].indexes = append(groups[p].indexes, i)
+	}
+	return groups
+}
@@ -1,22 +1,43 @@
 package kv
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"io"
 	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-const defaultCleanInterval = time.Second
-
 type Cache interface {
-	Add(key string, value T) bool
+	Add(key string, value T) error
 	Value(key string) (T, bool)
 	ListAll() []T
-	Remove(key string)
-	AddWithTtl(key string, value T, ttl time.Duration) bool
+	Remove(key string) error
+	AddWithTtl(key string, value T, ttl time.Duration) error
+	AddWithSlidingTtl(key string, value T, ttl time.Duration) error
+	Touch(key string) error
 	TimeAlive(key string) (time.Duration, bool)
-	SetTtl(key string, ttl *time.Time) bool
+	Ttl(key string) (time.Duration, *time.Time, bool)
+	SetTtl(key string, ttl *time.Time) error
+	Expire(key string, ttl time.Duration) error
+	Persist(key string) error
+	Set(key string, value T, ttl time.Duration, mode WriteMode) (bool, error)
+	Entry(key string) (TtlBox, bool)
+	Scan(opts ScanOptions) ([]Item, string)
+	MultiGet(keys []string) []GetResult
+	MultiSet(items []SetItem) ([]bool, error)
+	MultiDelete(keys []string) ([]bool, error)
+	Subscribe(opts SubscribeOptions) *Subscription
+	CompareAndSwap(key string, version uint64, value T, ttl time.Duration) (uint64, error)
+	Incr(key string, delta int64, ttl time.Duration, resetTtl bool) (int64, error)
+	Update(fn func(tx Txn) error) error
+	Lock(name, owner string, ttl time.Duration) (uint64, error)
+	Renew(name, owner string, token uint64, ttl time.Duration) error
+	Unlock(name, owner string, token uint64) error
+	Stats() Stats
+	Close(ctx context.Context) error
 }
 
 // Auxiliary struct to take care of TTL.
@@ -24,157 +45,719 @@ type TtlBox struct {
 	CreatedAt time.Time
 	Expired   *time.Time
 	Content   T
+	// Version is increased on every change of the entry. Versions are unique within the cache.
+	Version uint64
+	// Sliding is the TTL renewed by every read of the value if it is positive:
+	// reads move Expired to the moment of the read plus Sliding.
+	Sliding time.Duration
+}
+
+// IsExpired reports whether the expiration date of the box has passed by the given moment.
+func (b TtlBox) IsExpired(now time.Time) bool {
+	return b.Expired != nil && now.After(*b.Expired)
 }
 
-// T holds user's values.
+// WriteMode defines the condition on the existence of a key for a write to happen.
+type WriteMode int
+
+const (
+	// WriteAlways stores the value whether the key exists or not.
+	WriteAlways WriteMode = iota
+	// WriteIfAbsent stores the value only if the key is not in the cache.
+	WriteIfAbsent
+	// WriteIfPresent stores the value only if the key is already in the cache.
+	WriteIfPresent
+)
+
+// T holds user's values. V is an arbitrary payload, ContentType optionally describes it.
 type T struct {
-	V string
+	V           []byte
+	ContentType string
 }
 
 type cache struct {
 	config Configuration
-	mu     sync.RWMutex
-	values map[string]TtlBox
+	shards []*shard
+	log    LogStorage
+	clock  Clock
+	events *eventBus
+	// version is the last assigned entry version, accessed atomically.
+	version uint64
+	// evictions and expirations count deleted keys, accessed atomically.
+	evictions   uint64
+	expirations uint64
+	// txMu is held for reading by committing transactions and for writing by snapshots,
+	// so snapshots never contain a part of a transaction.
+	txMu sync.RWMutex
+
+	jobs      []*job
+	done      chan struct{}
+	workers   sync.WaitGroup
+	closeOnce sync.Once
 }
 
 func NewCache(config Configuration) Cache {
 	c := &cache{
-		mu:     sync.RWMutex{},
-		values: make(map[string]TtlBox),
+		done: make(chan struct{}),
 	}
 	c.configure(config)
-	c.startCleaner(defaultCleanInterval)
+	c.startCleaner(c.config.CleanInterval)
 	return c
 }
 
-// configure applies configuration, starts background processes if needed.
+// configure applies configuration, restores the data into shards,
+// starts background processes if needed.
 func (c *cache) configure(config Configuration) {
 	c.config = config
 	if c.config.Storage == nil {
 		c.config.Storage = &UnimplementedStorage{}
 	}
-	err := c.config.Storage.RestoreInto(&c.values)
+	if c.config.Clock == nil {
+		c.config.Clock = RealClock{}
+	}
+	c.clock = c.config.Clock
+	c.events = newEventBus(c.clock)
+	if c.config.CleanInterval <= 0 {
+		c.config.CleanInterval = DefaultCleanInterval
+	}
+	if c.config.ShardCount <= 0 {
+		c.config.ShardCount = DefaultShardCount
+	}
+	if c.config.MaxEntries > 0 && c.config.MaxEntries < c.config.ShardCount {
+		// Every shard holds at least one entry, so more shards would exceed the limit.
+		c.config.ShardCount = c.config.MaxEntries
+	}
+	_, trackDirty := c.config.Storage.(IncrementalStorage)
+	c.shards = make([]*shard, c.config.ShardCount)
+	for i := range c.shards {
+		c.shards[i] = newShard(trackDirty)
+	}
+	c.limitShards()
+
+	values := make(map[string]TtlBox)
+	err := c.config.Storage.RestoreInto(&values)
 	if err != nil {
 		log.Println(err)
 	}
+	for k, v := range values {
+		if v.Version > c.version {
+			c.version = v.Version
+		}
+		if k != versionKey {
+			c.shardFor(k).put(k, v)
+		}
+	}
+	now := c.clock.Now()
+	for _, s := range c.shards {
+		c.makeRoom(s, "", TtlBox{}, now)
+	}
+
+	if ls, ok := c.config.Storage.(LogStorage); ok {
+		c.log = ls
+		if c.config.SyncMode == SyncPeriodic {
+			if c.config.SyncInterval == 0 {
+				c.config.SyncInterval = DefaultSyncInterval
+			}
+			c.startLogSync()
+		}
+	}
 	if c.config.BackupInterval != 0 {
 		c.startAutoBackup()
 	}
 }
 
+// job runs a function periodically using timers of the cache clock until it is stopped.
+type job struct {
+	mu      sync.Mutex
+	clock   Clock
+	every   time.Duration
+	f       func()
+	timer   Timer
+	stopped bool
+}
+
+// every initiates background process that calls f every interval until the cache is closed.
+func (c *cache) every(interval time.Duration, f func()) {
+	j := &job{clock: c.clock, every: interval, f: f}
+	j.mu.Lock()
+	j.timer = j.clock.AfterFunc(j.every, j.run)
+	j.mu.Unlock()
+	c.jobs = append(c.jobs, j)
+}
+
+func (j *job) run() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.stopped {
+		return
+	}
+	j.f()
+	j.timer = j.clock.AfterFunc(j.every, j.run)
+}
+
+// stop cancels the next run of the job waiting for the current one to complete.
+func (j *job) stop() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.stopped = true
+	j.timer.Stop()
+}
+
 // startCleaner initiates background process that deletes expired pairs from cache.
 func (c *cache) startCleaner(delta time.Duration) {
-	tick := time.Tick(delta)
-	go func() {
-		for range tick {
-			c.mu.Lock()
-			now := time.Now()
-			for k, v := range c.values {
-				if v.Expired != nil && now.After(*v.Expired) {
-					fmt.Printf("deleted by cleaner: %s %v\n", k, v)
-					delete(c.values, k)
-				}
-			}
-			c.mu.Unlock()
+	c.every(delta, c.clean)
+}
+
+// clean deletes expired pairs locking one shard at a time.
+// Only the keys popped from the expiry index are visited.
+func (c *cache) clean() {
+	for _, s := range c.shards {
+		c.cleanShard(s)
+	}
+}
+
+func (c *cache) cleanShard(s *shard) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c.expireLocked(s, c.clock.Now())
+}
+
+// expireLocked deletes the expired keys of the shard held locked by the caller.
+// If the deletions cannot be journaled the keys are left for the next run.
+func (c *cache) expireLocked(s *shard, now time.Time) {
+	keys := s.expiry.popExpired(now)
+	if len(keys) == 0 {
+		return
+	}
+	changes := make([]Change, len(keys))
+	for i, k := range keys {
+		changes[i] = Change{Kind: ChangeDelete, Key: k, Event: EventExpire}
+	}
+	if err := c.journal(changes...); err != nil {
+		logChangeError(err)
+		for _, k := range keys {
+			s.expiry.set(k, s.values[k].Expired)
 		}
-	}()
+		return
+	}
+	for _, k := range keys {
+		box := s.values[k]
+		s.delete(k)
+		s.markDirty(k)
+		atomic.AddUint64(&c.expirations, 1)
+		c.events.publish(Event{Kind: EventExpire, Key: k, TtlBox: box})
+	}
 }
 
 // startAutoBackup initiates background process that makes snapshots of cache data.
 func (c *cache) startAutoBackup() {
-	tick := time.Tick(c.config.BackupInterval)
+	c.every(c.config.BackupInterval, func() {
+		if err := c.makeSnapshot(); err != nil {
+			log.Println(err)
+		}
+	})
+}
+
+// startLogSync initiates background process that periodically flushes the change log.
+func (c *cache) startLogSync() {
+	ticker := c.clock.NewTicker(c.config.SyncInterval)
+	c.workers.Add(1)
 	go func() {
-		for range tick {
-			c.makeSnapshot()
+		defer c.workers.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.done:
+				return
+			case <-ticker.C():
+				if err := c.log.Sync(); err != nil {
+					log.Println(err)
+				}
+			}
 		}
 	}()
 }
 
-func (c *cache) makeSnapshot() {
-	c.mu.RLock()
-	mapCopy := make(map[string]TtlBox, len(c.values))
-	for k, v := range c.values {
-		mapCopy[k] = v
+// makeSnapshot saves the cache data into the storage copying one shard at a time.
+// The change log is rotated before copying, so every change logged before
+// the rotation is already applied to the shards and gets into the snapshot.
+func (c *cache) makeSnapshot() error {
+	c.txMu.Lock()
+	defer c.txMu.Unlock()
+	if c.log != nil {
+		if err := c.log.Rotate(); err != nil {
+			return err
+		}
+	}
+	if is, ok := c.config.Storage.(IncrementalStorage); ok {
+		return c.saveChanges(is)
+	}
+	mapCopy := make(map[string]TtlBox)
+	for _, s := range c.shards {
+		s.mu.RLock()
+		for k, v := range s.values {
+			mapCopy[k] = v
+		}
+		s.mu.RUnlock()
+	}
+	mapCopy[versionKey] = c.versionBox()
+	return c.config.Storage.Save(mapCopy)
+}
+
+// saveChanges passes to the storage only the keys modified since the last backup.
+// If the storage fails to save them, the keys are kept to be saved next time.
+func (c *cache) saveChanges(is IncrementalStorage) error {
+	dirty := make([]map[string]struct{}, len(c.shards))
+	updated := make(map[string]TtlBox)
+	deleted := make([]string, 0)
+	for i, s := range c.shards {
+		s.mu.Lock()
+		dirty[i] = s.dirty
+		s.dirty = make(map[string]struct{})
+		for k := range dirty[i] {
+			if v, ok := s.values[k]; ok {
+				updated[k] = v
+			} else {
+				deleted = append(deleted, k)
+			}
+		}
+		s.mu.Unlock()
+	}
+	if len(updated) == 0 && len(deleted) == 0 {
+		return nil
+	}
+	updated[versionKey] = c.versionBox()
+	err := is.SaveChanges(updated, deleted)
+	if err != nil {
+		for i, s := range c.shards {
+			s.mu.Lock()
+			for k := range dirty[i] {
+				s.dirty[k] = struct{}{}
+			}
+			s.mu.Unlock()
+		}
+	}
+	return err
+}
+
+// journal appends the changes to the log storage if there is one.
+// Must be called with the write locks of the keys' shards held,
+// so the log order matches the order of changes. Empty keys and entries
+// larger than their shard are rejected, every write passes through here.
+func (c *cache) journal(changes ...Change) error {
+	for _, ch := range changes {
+		if ch.Kind != ChangeSet {
+			continue
+		}
+		if err := c.checkEntry(ch.Key, ch.Box); err != nil {
+			return err
+		}
+	}
+	if c.config.ReadOnly {
+		return ErrReadOnly
+	}
+	return c.record(changes...)
+}
+
+// checkEntry returns ErrInvalidKey for the empty key and ErrTooLarge for the entry that doesn't fit into its shard.
+func (c *cache) checkEntry(key string, box TtlBox) error {
+	if key == "" {
+		return ErrInvalidKey
+	}
+	if s := c.shardFor(key); s.maxBytes > 0 && entrySize(key, box) > s.maxBytes {
+		return ErrTooLarge
+	}
+	return nil
+}
+
+// record is journal for the changes repeated by Replica, that are accepted by read-only caches.
+// The current version is added to the deletions, the log may not have the versions of the deleted entries otherwise.
+func (c *cache) record(changes ...Change) error {
+	if c.log == nil {
+		return nil
+	}
+	for _, ch := range changes {
+		if ch.Kind == ChangeDelete {
+			changes = append(changes[:len(changes):len(changes)], Change{Kind: ChangeSet, Key: versionKey, Box: c.versionBox()})
+			break
+		}
 	}
-	c.mu.RUnlock()
-	if err := c.config.Storage.Save(mapCopy); err != nil {
+	if err := c.log.Append(changes...); err != nil {
+		return storageError(err)
+	}
+	if c.config.SyncMode == SyncAlways {
+		if err := c.log.Sync(); err != nil {
+			return storageError(err)
+		}
+	}
+	return nil
+}
+
+// logChangeError logs the failure to journal a change the cache makes on its own,
+// such as an expiration. Read-only storages are expected to refuse them.
+func logChangeError(err error) {
+	if !errors.Is(err, ErrReadOnly) {
 		log.Println(err)
 	}
 }
 
-// Add sets value for a key without TTL. If the key existed in the cache
-// the new value overwrites the old one.
-func (c *cache) Add(key string, value T) bool {
-	return c.add(key, value, nil)
+// Close stops the cleaner and the auto backup processes, closes subscriptions, saves the final
+// snapshot of the cache data into the storage and closes the storage if it
+// implements io.Closer. If the context is done before
+// the background processes have finished, its error is returned and
+// no snapshot is made. Subsequent calls do nothing and return nil.
+func (c *cache) Close(ctx context.Context) error {
+	var err error
+	c.closeOnce.Do(func() {
+		stopped := make(chan struct{})
+		go func() {
+			for _, j := range c.jobs {
+				j.stop()
+			}
+			close(c.done)
+			c.workers.Wait()
+			close(stopped)
+		}()
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		case <-stopped:
+		}
+		c.events.closeAll()
+		err = c.makeSnapshot()
+		if closer, ok := c.config.Storage.(io.Closer); ok {
+			if cErr := closer.Close(); err == nil {
+				err = cErr
+			}
+		}
+	})
+	return err
+}
+
+// Add sets value for a key without TTL, or with the default one if it is configured.
+// If the key existed in the cache the new value overwrites the old one.
+// An error is returned if the storage failed to record the change, the cache is left unchanged then.
+func (c *cache) Add(key string, value T) error {
+	_, err := c.add(key, value, c.expiration(c.clock.Now(), 0), 0, WriteAlways)
+	return err
 }
 
 // AddWithTtl sets value for a key and stores the expiration date for it.
 // If the key existed in the cache the new value overwrites the old one.
-func (c *cache) AddWithTtl(key string, value T, ttl time.Duration) bool {
-	expired := time.Now().Add(ttl)
-	return c.add(key, value, &expired)
+func (c *cache) AddWithTtl(key string, value T, ttl time.Duration) error {
+	expired := c.clock.Now().Add(ttl)
+	_, err := c.add(key, value, &expired, 0, WriteAlways)
+	return err
+}
+
+// AddWithSlidingTtl sets value for a key that expires after ttl since the last read.
+// If the key existed in the cache the new value overwrites the old one.
+func (c *cache) AddWithSlidingTtl(key string, value T, ttl time.Duration) error {
+	expired := c.clock.Now().Add(ttl)
+	_, err := c.add(key, value, &expired, ttl, WriteAlways)
+	return err
+}
+
+// Set stores value for a key if the existence of the key satisfies the mode.
+// A positive ttl sets the expiration date, otherwise the default TTL is used if it is configured.
+// Expired keys are considered absent. The boolean value reports whether the write happened.
+func (c *cache) Set(key string, value T, ttl time.Duration, mode WriteMode) (bool, error) {
+	return c.add(key, value, c.expiration(c.clock.Now(), ttl), 0, mode)
 }
 
 // Value returns the value for a given key.
 // The boolean value indicates the existence of the key in the cache.
+// Expired values are never returned even if the cleaner hasn't deleted them yet.
+// The sliding expiration of the key is renewed.
 func (c *cache) Value(key string) (T, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	value, ok := c.values[key]
+	value, ok := c.read(key)
 	return value.Content, ok
 }
 
-// ListAll returns the slice of all the values in cache.
+// ListAll returns the slice of all the values in cache except the expired ones.
 func (c *cache) ListAll() []T {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	results := make([]T, 0, len(c.values))
-	for _, b := range c.values {
-		results = append(results, b.Content)
+	results := make([]T, 0)
+	now := c.clock.Now()
+	for _, s := range c.shards {
+		s.mu.RLock()
+		for _, b := range s.values {
+			if !b.IsExpired(now) {
+				results = append(results, b.Content)
+			}
+		}
+		s.mu.RUnlock()
 	}
 	return results
 }
 
-// Remove removes value for a given key.
-func (c *cache) Remove(key string) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	delete(c.values, key)
+// Remove removes value for a given key, an absent key is not an error.
+func (c *cache) Remove(key string) error {
+	s := c.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := c.removeLocked(s, key, c.clock.Now())
+	return err
+}
+
+// removeLocked removes the key from the shard held locked by the caller.
+// Reports whether there was a value that hadn't expired.
+func (c *cache) removeLocked(s *shard, key string, now time.Time) (bool, error) {
+	old, ok := s.values[key]
+	if !ok {
+		return false, nil
+	}
+	if err := c.journal(Change{Kind: ChangeDelete, Key: key}); err != nil {
+		return false, err
+	}
+	s.delete(key)
+	s.markDirty(key)
+	c.events.publish(Event{Kind: EventDelete, Key: key, TtlBox: old})
+	return !old.IsExpired(now), nil
 }
 
 // TimeAlive returns the duration of how long the value has been in the cache.
 // The boolean value indicates the existence of the key in the cache.
 func (c *cache) TimeAlive(key string) (time.Duration, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	value, ok := c.values[key]
+	value, ok := c.lookup(key)
 	if !ok {
 		return 0, false
 	}
-	return time.Now().Sub(value.CreatedAt), true
+	return c.clock.Now().Sub(value.CreatedAt), true
 }
 
-// SetTtl changes previous expiration time for the key if it is in the cache.
-// Otherwise false is returned
-func (c *cache) SetTtl(key string, ttl *time.Time) bool {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	value, ok := c.values[key]
+// Ttl returns the time left until the key expires and its expiration date.
+// The date is nil if the key doesn't expire. The boolean value indicates the existence
+// of the key in the cache. Unlike reads of the value, Ttl doesn't renew the sliding expiration.
+func (c *cache) Ttl(key string) (time.Duration, *time.Time, bool) {
+	value, ok := c.lookup(key)
 	if !ok {
-		return false
+		return 0, nil, false
+	}
+	if value.Expired == nil {
+		return 0, nil, true
+	}
+	return value.Expired.Sub(c.clock.Now()), value.Expired, true
+}
+
+// SetTtl changes previous expiration time for the key if it is in the cache
+// and hasn't expired, the sliding expiration of the key is turned off. Otherwise ErrNotFound is returned.
+func (c *cache) SetTtl(key string, ttl *time.Time) error {
+	s := c.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[key]
+	if !ok || value.IsExpired(c.clock.Now()) {
+		return ErrNotFound
 	}
 	value.Expired = ttl
-	c.values[key] = value
-	return true
+	value.Sliding = 0
+	value.Version = c.nextVersion()
+	if err := c.journal(Change{Kind: ChangeSet, Key: key, Box: value, Event: EventTtl}); err != nil {
+		return err
+	}
+	s.put(key, value)
+	s.markDirty(key)
+	c.events.publish(Event{Kind: EventTtl, Key: key, TtlBox: value})
+	return nil
+}
+
+// Expire sets the expiration date of the key to ttl from now, see SetTtl.
+// A non-positive ttl makes the key expire right away.
+func (c *cache) Expire(key string, ttl time.Duration) error {
+	expired := c.clock.Now().Add(ttl)
+	return c.SetTtl(key, &expired)
+}
+
+// Persist removes the expiration date of the key, so it never expires, see SetTtl.
+func (c *cache) Persist(key string) error {
+	return c.SetTtl(key, nil)
+}
+
+// Entry returns the value for a given key along with its version and TTL data.
+// The boolean value indicates the existence of the key in the cache.
+// The sliding expiration of the key is renewed.
+func (c *cache) Entry(key string) (TtlBox, bool) {
+	return c.read(key)
 }
 
-func (c *cache) add(key string, value T, ttl *time.Time) bool {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.values[key] = TtlBox{
-		CreatedAt: time.Now(),
+// Touch renews the sliding expiration of the key, ErrNotFound is returned if the key is not in the cache.
+func (c *cache) Touch(key string) error {
+	_, ok, err := c.slide(key)
+	if err == nil && !ok {
+		return ErrNotFound
+	}
+	return err
+}
+
+// read returns the box for the key like lookup renewing its sliding expiration.
+// The value is returned even if the renewal fails, reads don't fail because of the storage.
+func (c *cache) read(key string) (TtlBox, bool) {
+	box, ok := c.lookup(key)
+	if ok && box.Sliding > 0 {
+		box, ok, err := c.slide(key)
+		if err != nil {
+			logChangeError(err)
+		}
+		return box, ok
+	}
+	return box, ok
+}
+
+// slide moves the expiration date of the key with sliding TTL forward.
+// The version of the entry is kept and EventSlide is published, as the value didn't change.
+// If the storage fails to record the renewal, the stored box is returned with the error.
+func (c *cache) slide(key string) (TtlBox, bool, error) {
+	s := c.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := c.clock.Now()
+	box, ok := s.values[key]
+	if !ok || box.IsExpired(now) {
+		return TtlBox{}, false, nil
+	}
+	if box.Sliding <= 0 {
+		s.touch(key)
+		return box, true, nil
+	}
+	expired := now.Add(box.Sliding)
+	box.Expired = &expired
+	if err := c.journal(Change{Kind: ChangeSet, Key: key, Box: box, Event: EventSlide}); err != nil {
+		return s.values[key], true, err
+	}
+	s.put(key, box)
+	s.markDirty(key)
+	c.events.publish(Event{Kind: EventSlide, Key: key, TtlBox: box})
+	return box, true, nil
+}
+
+// CompareAndSwap stores the value only if the current version of the key equals
+// the given one. Zero version means that the key must be absent. A positive ttl
+// sets the expiration date, otherwise the default TTL is used if it is configured.
+// Returns the new version or ErrNotFound / ErrExists for zero version / ErrVersionMismatch.
+func (c *cache) CompareAndSwap(key string, version uint64, value T, ttl time.Duration) (uint64, error) {
+	s := c.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := c.clock.Now()
+	old, ok := s.values[key]
+	if ok && old.IsExpired(now) {
+		ok = false
+	}
+	switch {
+	case !ok && version != 0:
+		return 0, ErrNotFound
+	case ok && version == 0:
+		return 0, ErrExists
+	case ok && old.Version != version:
+		return 0, ErrVersionMismatch
+	}
+	box := TtlBox{
+		CreatedAt: now,
+		Expired:   c.expiration(now, ttl),
+		Content:   value,
+		Version:   c.nextVersion(),
+	}
+	if err := c.journal(Change{Kind: ChangeSet, Key: key, Box: box}); err != nil {
+		return 0, err
+	}
+	c.makeRoom(s, key, box, now)
+	s.put(key, box)
+	s.markDirty(key)
+	c.events.publish(Event{Kind: EventSet, Key: key, TtlBox: box})
+	return box.Version, nil
+}
+
+// expiration returns the expiration date for a value stored with the ttl at the moment.
+// Non-positive ttl is replaced by the default one, nil means that the value doesn't expire.
+func (c *cache) expiration(now time.Time, ttl time.Duration) *time.Time {
+	if ttl <= 0 {
+		ttl = c.config.DefaultTtl
+	}
+	if ttl <= 0 {
+		return nil
+	}
+	expired := now.Add(ttl)
+	return &expired
+}
+
+func (c *cache) nextVersion() uint64 {
+	return atomic.AddUint64(&c.version, 1)
+}
+
+// versionBox is the entry saved under versionKey.
+func (c *cache) versionBox() TtlBox {
+	return TtlBox{Version: atomic.LoadUint64(&c.version)}
+}
+
+// lookup returns the box for the key treating an expired entry as absent.
+// If DeleteExpiredOnRead is configured, the expired entry is deleted right away.
+func (c *cache) lookup(key string) (TtlBox, bool) {
+	s := c.shardFor(key)
+	now := c.clock.Now()
+	s.mu.RLock()
+	box, ok := s.values[key]
+	if ok && !box.IsExpired(now) {
+		s.touch(key)
+	}
+	s.mu.RUnlock()
+	if !ok {
+		return TtlBox{}, false
+	}
+	if !box.IsExpired(now) {
+		return box, true
+	}
+	if c.config.DeleteExpiredOnRead {
+		c.deleteExpired(s, key, now)
+	}
+	return TtlBox{}, false
+}
+
+// deleteExpired deletes the key if it is still expired after the write lock is taken.
+func (c *cache) deleteExpired(s *shard, key string, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if box, ok := s.values[key]; ok && box.IsExpired(now) {
+		if err := c.journal(Change{Kind: ChangeDelete, Key: key, Event: EventExpire}); err != nil {
+			logChangeError(err)
+			return
+		}
+		s.delete(key)
+		s.markDirty(key)
+		atomic.AddUint64(&c.expirations, 1)
+		c.events.publish(Event{Kind: EventExpire, Key: key, TtlBox: box})
+	}
+}
+
+func (c *cache) add(key string, value T, ttl *time.Time, sliding time.Duration, mode WriteMode) (bool, error) {
+	s := c.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return c.addLocked(s, key, value, ttl, sliding, mode, c.clock.Now())
+}
+
+// addLocked stores the value in the shard held locked by the caller if the mode allows.
+func (c *cache) addLocked(s *shard, key string, value T, ttl *time.Time, sliding time.Duration, mode WriteMode, now time.Time) (bool, error) {
+	if mode != WriteAlways {
+		old, ok := s.values[key]
+		exists := ok && !old.IsExpired(now)
+		if mode == WriteIfAbsent && exists || mode == WriteIfPresent && !exists {
+			return false, nil
+		}
+	}
+	box := TtlBox{
+		CreatedAt: now,
 		Expired:   ttl,
 		Content:   value,
+		Version:   c.nextVersion(),
+		Sliding:   sliding,
+	}
+	if err := c.journal(Change{Kind: ChangeSet, Key: key, Box: box}); err != nil {
+		return false, err
 	}
-	return true
+	c.makeRoom(s, key, box, now)
+	s.put(key, box)
+	s.markDirty(key)
+	c.events.publish(Event{Kind: EventSet, Key: key, TtlBox: box})
+	return true, nil
 }
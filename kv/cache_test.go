@@ -0,0 +1,467 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// shardCounts lists the configurations every behavior test runs against.
+var shardCounts = []int{1, DefaultShardCount}
+
+// forEachShardCount runs the test against caches with a single and multiple shards.
+// The caches are driven by a fake clock.
+func forEachShardCount(t *testing.T, test func(t *testing.T, cache Cache, clock *FakeClock)) {
+	for _, n := range shardCounts {
+		t.Run(fmt.Sprintf("shards=%d", n), func(t *testing.T) {
+			clock := NewFakeClock(time.Now())
+			cache := NewCache(Configuration{ShardCount: n, Clock: clock})
+			defer cache.Close(context.Background())
+			test(t, cache, clock)
+		})
+	}
+}
+
+func TestAddValueRemove(t *testing.T) {
+	forEachShardCount(t, func(t *testing.T, cache Cache, _ *FakeClock) {
+		for i := 0; i < 100; i++ {
+			cache.Add(strconv.Itoa(i), T{V: []byte(strconv.Itoa(i))})
+		}
+		cache.Add("1", T{V: []byte("one")})
+		if v, ok := cache.Value("1"); !ok || string(v.V) != "one" {
+			t.Errorf("expected overwritten value, got %v %v", v, ok)
+		}
+		cache.Remove("2")
+		if _, ok := cache.Value("2"); ok {
+			t.Error("expected #2 to be removed")
+		}
+		if all := cache.ListAll(); len(all) != 99 {
+			t.Errorf("expected 99 values, got %d", len(all))
+		}
+	})
+}
+
+func TestTtl(t *testing.T) {
+	forEachShardCount(t, func(t *testing.T, cc Cache, clock *FakeClock) {
+		c := cc.(*cache)
+		c.AddWithTtl("short", T{V: []byte("short")}, 1500*time.Millisecond)
+		c.AddWithTtl("extended", T{V: []byte("extended")}, time.Second)
+		c.Add("forever", T{V: []byte("forever")})
+		if err := c.SetTtl("missing", nil); err != ErrNotFound {
+			t.Error("expected ttl not to be set for missing key")
+		}
+		later := clock.Now().Add(5 * time.Second)
+		if err := c.SetTtl("extended", &later); err != nil {
+			t.Error("expected ttl to be set for existing key")
+		}
+
+		clock.Advance(time.Second)
+		if alive, ok := c.TimeAlive("forever"); !ok || alive != time.Second {
+			t.Errorf("unexpected time alive: %v %v", alive, ok)
+		}
+		clock.Advance(time.Second)
+		if c.stored("short") {
+			t.Error("expected expired value to be deleted by cleaner")
+		}
+		if !c.stored("extended") {
+			t.Error("expected value with extended ttl to stay")
+		}
+		clock.Advance(4 * time.Second)
+		if c.stored("extended") {
+			t.Error("expected value with extended ttl to be deleted by cleaner")
+		}
+		if !c.stored("forever") {
+			t.Error("expected value without ttl to stay")
+		}
+	})
+}
+
+func TestWriteModes(t *testing.T) {
+	forEachShardCount(t, func(t *testing.T, c Cache, clock *FakeClock) {
+		if ok, _ := c.Set("lock", T{V: []byte("a")}, 0, WriteIfPresent); ok {
+			t.Error("expected no write to absent key if present")
+		}
+		if ok, _ := c.Set("lock", T{V: []byte("a")}, time.Second, WriteIfAbsent); !ok {
+			t.Error("expected write to absent key if absent")
+		}
+		if ok, _ := c.Set("lock", T{V: []byte("b")}, 0, WriteIfAbsent); ok {
+			t.Error("expected no write to present key if absent")
+		}
+		if v, _ := c.Value("lock"); string(v.V) != "a" {
+			t.Errorf("expected value to stay, got %v", v)
+		}
+		if ok, _ := c.Set("lock", T{V: []byte("c")}, time.Second, WriteIfPresent); !ok {
+			t.Error("expected write to present key if present")
+		}
+		clock.Advance(2 * time.Second)
+		if ok, _ := c.Set("lock", T{V: []byte("d")}, 0, WriteIfPresent); ok {
+			t.Error("expected expired key to be considered absent")
+		}
+		if ok, _ := c.Set("lock", T{V: []byte("e")}, 0, WriteIfAbsent); !ok {
+			t.Error("expected write to expired key if absent")
+		}
+		if ok, _ := c.Set("lock", T{V: []byte("f")}, 0, WriteAlways); !ok {
+			t.Error("expected unconditional write")
+		}
+		if v, _ := c.Value("lock"); string(v.V) != "f" {
+			t.Errorf("expected last written value, got %v", v)
+		}
+	})
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	forEachShardCount(t, func(t *testing.T, c Cache, clock *FakeClock) {
+		if _, err := c.CompareAndSwap("counter", 1, T{V: []byte("1")}, 0); err != ErrNotFound {
+			t.Errorf("expected not found, got %v", err)
+		}
+		v1, err := c.CompareAndSwap("counter", 0, T{V: []byte("1")}, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.CompareAndSwap("counter", 0, T{V: []byte("2")}, 0); err != ErrExists {
+			t.Errorf("expected exists for existing key, got %v", err)
+		}
+		v2, err := c.CompareAndSwap("counter", v1, T{V: []byte("2")}, time.Second)
+		if err != nil || v2 <= v1 {
+			t.Fatalf("expected greater version, got %d %v", v2, err)
+		}
+		if _, err := c.CompareAndSwap("counter", v1, T{V: []byte("3")}, 0); err != ErrVersionMismatch {
+			t.Errorf("expected version mismatch for stale version, got %v", err)
+		}
+		if box, ok := c.Entry("counter"); !ok || box.Version != v2 || string(box.Content.V) != "2" {
+			t.Errorf("unexpected entry: %v %v", box, ok)
+		}
+		clock.Advance(2 * time.Second)
+		if _, err := c.CompareAndSwap("counter", 0, T{V: []byte("4")}, 0); err != nil {
+			t.Errorf("expected expired key to be considered absent, got %v", err)
+		}
+	})
+}
+
+func TestBatch(t *testing.T) {
+	forEachShardCount(t, func(t *testing.T, c Cache, clock *FakeClock) {
+		c.Add("existing", T{V: []byte("old")})
+		written, err := c.MultiSet([]SetItem{
+			{Key: "a", Value: T{V: []byte("a")}},
+			{Key: "b", Value: T{V: []byte("b")}, Ttl: time.Second},
+			{Key: "existing", Value: T{V: []byte("new")}, Mode: WriteIfAbsent},
+			{Key: "a", Value: T{V: []byte("a2")}, Mode: WriteIfPresent},
+		})
+		if err != nil || !reflect.DeepEqual(written, []bool{true, true, false, true}) {
+			t.Errorf("unexpected written flags: %v %v", written, err)
+		}
+
+		results := c.MultiGet([]string{"a", "missing", "existing", "b"})
+		values := make([]string, len(results))
+		for i, r := range results {
+			values[i] = fmt.Sprintf("%v:%s", r.Found, r.Content.V)
+		}
+		if !reflect.DeepEqual(values, []string{"true:a2", "false:", "true:old", "true:b"}) {
+			t.Errorf("unexpected results: %v", values)
+		}
+
+		clock.Advance(2 * time.Second)
+		deleted, err := c.MultiDelete([]string{"a", "b", "missing", "a"})
+		if err != nil || !reflect.DeepEqual(deleted, []bool{true, false, false, false}) {
+			t.Errorf("unexpected deleted flags: %v %v", deleted, err)
+		}
+		if all := c.ListAll(); len(all) != 1 {
+			t.Errorf("expected only the existing value to stay, got %v", all)
+		}
+
+		// Nothing is written if any of the items is invalid.
+		written, err = c.MultiSet([]SetItem{
+			{Key: "a", Value: T{V: []byte("a")}},
+			{Key: "b", Value: T{V: []byte("b")}},
+			{Key: "", Value: T{V: []byte("empty")}},
+		})
+		var keyErr *KeyError
+		if !errors.As(err, &keyErr) || keyErr.Key != "" || !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("expected the invalid key error, got %v", err)
+		}
+		if !reflect.DeepEqual(written, []bool{false, false, false}) {
+			t.Errorf("unexpected written flags: %v", written)
+		}
+		if _, ok := c.Value("a"); ok {
+			t.Error("expected a not to be written")
+		}
+	})
+}
+
+// stored reports whether the key is kept in the cache regardless of its expiration.
+func (c *cache) stored(key string) bool {
+	s := c.shardFor(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.values[key]
+	return ok
+}
+
+// countingStorage counts the snapshots saved by the cache.
+type countingStorage struct {
+	UnimplementedStorage
+	saved int
+}
+
+func (s *countingStorage) Save(map[string]TtlBox) error {
+	s.saved++
+	return nil
+}
+
+func TestAutoBackup(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	storage := &countingStorage{}
+	cache := NewCache(Configuration{Storage: storage, BackupInterval: time.Minute, Clock: clock})
+	clock.Advance(59 * time.Second)
+	if storage.saved != 0 {
+		t.Errorf("expected no backups yet, got %d", storage.saved)
+	}
+	clock.Advance(10 * time.Minute)
+	if storage.saved != 10 {
+		t.Errorf("expected 10 backups, got %d", storage.saved)
+	}
+	if err := cache.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(10 * time.Minute)
+	if storage.saved != 11 {
+		t.Errorf("expected only the final backup after close, got %d", storage.saved)
+	}
+}
+
+// incrementalStorage records the changes passed by the cache.
+type incrementalStorage struct {
+	UnimplementedStorage
+	updated []string
+	deleted []string
+}
+
+func (s *incrementalStorage) SaveChanges(updated map[string]TtlBox, deleted []string) error {
+	for k := range updated {
+		s.updated = append(s.updated, k)
+	}
+	s.deleted = append(s.deleted, deleted...)
+	return nil
+}
+
+// Modifies several keys and closes the cache. Only the modified keys and the version
+// must be passed to the storage, the removed ones as deletions. Removing a missing key changes nothing.
+func TestIncrementalBackup(t *testing.T) {
+	storage := &incrementalStorage{}
+	cache := NewCache(Configuration{Storage: storage})
+	cache.Add("1", T{V: []byte("one")})
+	cache.Add("2", T{V: []byte("two")})
+	cache.Add("3", T{V: []byte("three")})
+	cache.Remove("2")
+	cache.Remove("4")
+	if err := cache.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(storage.updated)
+	sort.Strings(storage.deleted)
+	if !reflect.DeepEqual(storage.updated, []string{versionKey, "1", "3"}) {
+		t.Errorf("unexpected updated keys: %v", storage.updated)
+	}
+	if !reflect.DeepEqual(storage.deleted, []string{"2"}) {
+		t.Errorf("unexpected deleted keys: %v", storage.deleted)
+	}
+}
+
+// Parallel reads and writes over a set of keys, every 10th operation is a write.
+// Compare the results for a single shard with the default number of shards.
+func BenchmarkParallelReadWrite(b *testing.B) {
+	for _, n := range shardCounts {
+		b.Run(fmt.Sprintf("shards=%d", n), func(b *testing.B) {
+			cache := NewCache(Configuration{ShardCount: n})
+			defer cache.Close(context.Background())
+			keys := make([]string, 1024)
+			for i := range keys {
+				keys[i] = strconv.Itoa(i)
+				cache.Add(keys[i], T{V: []byte(keys[i])})
+			}
+			var seed int64
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := int(atomic.AddInt64(&seed, 1)) * 7919
+				for pb.Next() {
+					key := keys[i%len(keys)]
+					if i%10 == 0 {
+						cache.Add(key, T{V: []byte(key)})
+					} else {
+						cache.Value(key)
+					}
+					i++
+				}
+			})
+		})
+	}
+}
+
+// Parallel reads while the cleaner scans a large cache.
+func BenchmarkReadDuringClean(b *testing.B) {
+	for _, n := range shardCounts {
+		b.Run(fmt.Sprintf("shards=%d", n), func(b *testing.B) {
+			c := NewCache(Configuration{ShardCount: n}).(*cache)
+			defer c.Close(context.Background())
+			for i := 0; i < 100000; i++ {
+				c.AddWithTtl(strconv.Itoa(i), T{V: []byte("v")}, time.Hour)
+			}
+			stop := make(chan struct{})
+			defer close(stop)
+			go func() {
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+						c.clean()
+					}
+				}
+			}()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					c.Value(strconv.Itoa(i % 100000))
+					i++
+				}
+			})
+		})
+	}
+}
+
+// Expired values must not be readable even though the cleaner has not run.
+func TestReadsSkipExpired(t *testing.T) {
+	for _, deleteOnRead := range []bool{false, true} {
+		t.Run(fmt.Sprintf("delete=%v", deleteOnRead), func(t *testing.T) {
+			clock := NewFakeClock(time.Now())
+			c := NewCache(Configuration{CleanInterval: time.Hour, DeleteExpiredOnRead: deleteOnRead, Clock: clock}).(*cache)
+			defer c.Close(context.Background())
+
+			c.AddWithTtl("session", T{V: []byte("token")}, time.Second)
+			c.Add("forever", T{V: []byte("forever")})
+			if _, ok := c.Value("session"); !ok {
+				t.Fatal("expected value before expiration")
+			}
+			clock.Advance(time.Second + time.Nanosecond)
+
+			if _, ok := c.Value("session"); ok {
+				t.Error("expected expired value to be absent")
+			}
+			if _, ok := c.TimeAlive("session"); ok {
+				t.Error("expected no time alive for expired value")
+			}
+			if err := c.SetTtl("session", nil); err != ErrNotFound {
+				t.Error("expected ttl not to be set for expired value")
+			}
+			if all := c.ListAll(); !reflect.DeepEqual(all, []T{{V: []byte("forever")}}) {
+				t.Errorf("unexpected values: %v", all)
+			}
+			if stored := c.stored("session"); stored == deleteOnRead {
+				t.Errorf("expected expired value to be stored: %v, got %v", !deleteOnRead, stored)
+			}
+		})
+	}
+}
+
+func TestSlidingTtl(t *testing.T) {
+	forEachShardCount(t, func(t *testing.T, c Cache, clock *FakeClock) {
+		c.AddWithSlidingTtl("session", T{V: []byte("data")}, time.Minute)
+		box, _ := c.Entry("session")
+		version := box.Version
+		for i := 0; i < 3; i++ {
+			clock.Advance(40 * time.Second)
+			if _, ok := c.Value("session"); !ok {
+				t.Fatalf("expected read %d to renew the ttl", i)
+			}
+		}
+		clock.Advance(40 * time.Second)
+		if err := c.Touch("session"); err != nil {
+			t.Fatal("expected touch to renew the ttl")
+		}
+		box, _ = c.Entry("session")
+		if box.Version != version || !box.Expired.Equal(clock.Now().Add(time.Minute)) {
+			t.Errorf("expected the same version and renewed expiration, got %d %v", box.Version, box.Expired)
+		}
+		if d, ok := c.TimeAlive("session"); !ok || d != 160*time.Second {
+			t.Errorf("expected the age to be kept, got %v %v", d, ok)
+		}
+		clock.Advance(61 * time.Second)
+		if _, ok := c.Value("session"); ok {
+			t.Error("expected the session to expire without reads")
+		}
+		if err := c.Touch("session"); err != ErrNotFound {
+			t.Error("expected touch of an expired key to fail")
+		}
+	})
+}
+
+// failingLog is a log storage refusing the changes while failing is set.
+type failingLog struct {
+	UnimplementedStorage
+	failing bool
+}
+
+func (l *failingLog) Append(changes ...Change) error {
+	if l.failing {
+		return errors.New("disk is full")
+	}
+	return nil
+}
+
+func (l *failingLog) Sync() error   { return nil }
+func (l *failingLog) Rotate() error { return nil }
+
+// The writes the storage fails to record are not applied and their errors wrap ErrUnavailable.
+func TestStorageFailure(t *testing.T) {
+	storage := &failingLog{}
+	c := NewCache(Configuration{Storage: storage})
+	defer c.Close(context.Background())
+	c.Add("a", T{V: []byte("a")})
+	c.AddWithSlidingTtl("s", T{V: []byte("s")}, time.Minute)
+	storage.failing = true
+
+	if err := c.Add("b", T{V: []byte("b")}); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected add to fail, got %v", err)
+	}
+	if written, err := c.Set("a", T{V: []byte("new")}, 0, WriteIfPresent); written || !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected set to fail, got %v %v", written, err)
+	}
+	if err := c.Remove("a"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected remove to fail, got %v", err)
+	}
+	if err := c.Expire("a", time.Second); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected expire to fail, got %v", err)
+	}
+	if err := c.Touch("s"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected touch to fail, got %v", err)
+	}
+	if _, err := c.MultiSet([]SetItem{{Key: "b", Value: T{V: []byte("b")}}}); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected multi set to fail, got %v", err)
+	}
+	if _, err := c.MultiDelete([]string{"a"}); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected multi delete to fail, got %v", err)
+	}
+	if v, ok := c.Value("a"); !ok || string(v.V) != "a" {
+		t.Errorf("expected a to stay unchanged, got %v %v", v, ok)
+	}
+	if _, expired, _ := c.Ttl("a"); expired != nil {
+		t.Errorf("expected a not to expire, got %v", expired)
+	}
+	if _, ok := c.Value("b"); ok {
+		t.Error("expected b not to be stored")
+	}
+
+	readOnly := NewCache(Configuration{ReadOnly: true})
+	defer readOnly.Close(context.Background())
+	if err := readOnly.Add("a", T{}); err != ErrReadOnly {
+		t.Errorf("expected ErrReadOnly, got %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package kv
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the current time and timers to the cache.
+// Replacing it allows to control TTL and background processes in tests.
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Ticker delivers ticks at intervals, see time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Timer calls a function once after a duration, see time.Timer.
+type Timer interface {
+	Stop() bool
+}
+
+// RealClock implements Clock with the functions of the time package.
+type RealClock struct{}
+
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+func (RealClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+func (RealClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+// FakeClock is a Clock that moves only when Advance is called.
+// Functions scheduled with AfterFunc are run synchronously by Advance,
+// tickers receive ticks without blocking like the real ones.
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers map[*fakeTimer]struct{}
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{
+		now:    now,
+		timers: make(map[*fakeTimer]struct{}),
+	}
+}
+
+// fakeTimer serves both as a timer and a ticker of the FakeClock.
+type fakeTimer struct {
+	clock  *FakeClock
+	when   time.Time
+	period time.Duration
+	f      func()
+	c      chan time.Time
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, when: c.now.Add(d), period: d, c: make(chan time.Time, 1)}
+	c.timers[t] = struct{}{}
+	return fakeTicker{t}
+}
+
+func (c *FakeClock) AfterFunc(d time.Duration, f func()) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, when: c.now.Add(d), f: f}
+	c.timers[t] = struct{}{}
+	return t
+}
+
+// Advance moves the clock forward firing all the timers and tickers due
+// in chronological order.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	target := c.now.Add(d)
+	c.mu.Unlock()
+	for {
+		c.mu.Lock()
+		var next *fakeTimer
+		for t := range c.timers {
+			if !t.when.After(target) && (next == nil || t.when.Before(next.when)) {
+				next = t
+			}
+		}
+		if next == nil {
+			c.now = target
+			c.mu.Unlock()
+			return
+		}
+		c.now = next.when
+		if next.period > 0 {
+			next.when = next.when.Add(next.period)
+			select {
+			case next.c <- c.now:
+			default:
+			}
+			c.mu.Unlock()
+			continue
+		}
+		delete(c.timers, next)
+		c.mu.Unlock()
+		next.f()
+	}
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	_, pending := t.clock.timers[t]
+	delete(t.clock.timers, t)
+	return pending
+}
+
+type fakeTicker struct {
+	*fakeTimer
+}
+
+func (t fakeTicker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t fakeTicker) Stop() {
+	t.fakeTimer.Stop()
+}
@@ -4,8 +4,53 @@ import "time"
 
 const DefaultBackupInterval = 5 * time.Second
 
+const DefaultCleanInterval = time.Second
+
+const DefaultSyncInterval = 100 * time.Millisecond
+
+// SyncMode defines how often the changes appended to a LogStorage are flushed to disk.
+type SyncMode int
+
+const (
+	// SyncAlways flushes every change before the cache method returns.
+	SyncAlways SyncMode = iota
+	// SyncPeriodic flushes changes in the background every SyncInterval.
+	SyncPeriodic
+	// SyncNever leaves flushing to the operating system.
+	SyncNever
+)
+
 // Configuration defines set of parameters to configure a cache.
 type Configuration struct {
 	BackupInterval time.Duration
 	Storage        Storage
+	SyncMode       SyncMode
+	SyncInterval   time.Duration
+	// CleanInterval is the period between runs of the cleaner that deletes expired values.
+	// DefaultCleanInterval is used if it is not set.
+	CleanInterval time.Duration
+	// DeleteExpiredOnRead makes read operations delete the expired values they come across
+	// instead of leaving them for the cleaner. Expired values are never returned either way.
+	DeleteExpiredOnRead bool
+	// ReadOnly makes every change of the cache fail with ErrReadOnly, including the ones the cache
+	// makes on its own: expirations, evictions and sliding renewals. The data is changed only by
+	// Replica, so replication followers repeat the changes of the leader and nothing else.
+	ReadOnly bool
+	// Clock provides time to the cache, RealClock is used if it is not set.
+	Clock Clock
+	// ShardCount is the number of independently locked parts of the cache.
+	// DefaultShardCount is used if it is not set.
+	ShardCount int
+	// MaxEntries and MaxBytes limit the number of entries and their estimated size,
+	// zero means no limit. The limits are split evenly between shards, so every
+	// shard starts evicting on its own once it holds its part of them.
+	// ShardCount is reduced to MaxEntries if it is greater. Entries larger than
+	// the part of MaxBytes of a shard are rejected with ErrTooLarge.
+	MaxEntries int
+	MaxBytes   int64
+	// DefaultTtl is applied to the values stored without TTL if it is positive.
+	DefaultTtl time.Duration
+	// NewEvictionPolicy creates the policy choosing the keys to evict for every shard
+	// when the size is limited. NewLruPolicy is used if it is not set.
+	NewEvictionPolicy func() EvictionPolicy
 }
@@ -0,0 +1,47 @@
+package kv
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// Incr atomically adds delta to the integer stored as a decimal string under the key
+// and returns the new value. An absent or expired key is created with the value of delta,
+// a positive ttl sets its expiration date. The expiration date of an existing key is kept
+// unless resetTtl is set, then a positive ttl replaces it and zero removes it.
+// Zero ttl stands for the default TTL if it is configured.
+func (c *cache) Incr(key string, delta int64, ttl time.Duration, resetTtl bool) (int64, error) {
+	s := c.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := c.clock.Now()
+	box, ok := s.values[key]
+	if !ok || box.IsExpired(now) {
+		box = TtlBox{CreatedAt: now}
+		resetTtl = true
+	} else {
+		current, err := strconv.ParseInt(string(box.Content.V), 10, 64)
+		if err != nil {
+			return 0, ErrNotNumeric
+		}
+		if delta > 0 && current > math.MaxInt64-delta || delta < 0 && current < math.MinInt64-delta {
+			return 0, ErrOverflow
+		}
+		delta += current
+	}
+	if resetTtl {
+		box.Expired = c.expiration(now, ttl)
+		box.Sliding = 0
+	}
+	box.Content.V = []byte(strconv.FormatInt(delta, 10))
+	box.Version = c.nextVersion()
+	if err := c.journal(Change{Kind: ChangeSet, Key: key, Box: box}); err != nil {
+		return 0, err
+	}
+	c.makeRoom(s, key, box, now)
+	s.put(key, box)
+	s.markDirty(key)
+	c.events.publish(Event{Kind: EventSet, Key: key, TtlBox: box})
+	return delta, nil
+}
@@ -0,0 +1,41 @@
+package kv
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestIncr(t *testing.T) {
+	forEachShardCount(t, func(t *testing.T, c Cache, clock *FakeClock) {
+		if v, err := c.Incr("hits", 5, time.Minute, false); err != nil || v != 5 {
+			t.Fatalf("expected counter to be created with 5, got %d %v", v, err)
+		}
+		clock.Advance(30 * time.Second)
+		if v, err := c.Incr("hits", -2, time.Hour, false); err != nil || v != 3 {
+			t.Fatalf("expected 3, got %d %v", v, err)
+		}
+		clock.Advance(31 * time.Second)
+		if v, err := c.Incr("hits", 1, time.Minute, false); err != nil || v != 1 {
+			t.Fatalf("expected the initial ttl to be kept and the counter to expire, got %d %v", v, err)
+		}
+		if v, err := c.Incr("hits", 1, 0, true); err != nil || v != 2 {
+			t.Fatalf("expected 2, got %d %v", v, err)
+		}
+		if box, _ := c.Entry("hits"); box.Expired != nil {
+			t.Errorf("expected ttl to be removed, got %v", box.Expired)
+		}
+
+		c.Add("name", T{V: []byte("john")})
+		if _, err := c.Incr("name", 1, 0, false); err != ErrNotNumeric {
+			t.Errorf("expected not numeric error, got %v", err)
+		}
+		c.Add("max", T{V: []byte("9223372036854775806")})
+		if _, err := c.Incr("max", 2, 0, false); err != ErrOverflow {
+			t.Errorf("expected overflow error, got %v", err)
+		}
+		if v, err := c.Incr("max", 1, 0, false); err != nil || v != math.MaxInt64 {
+			t.Errorf("expected max int64, got %d %v", v, err)
+		}
+	})
+}
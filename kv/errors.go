@@ -0,0 +1,69 @@
+package kv
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound is returned when the key is absent from the cache or expired.
+	ErrNotFound = errors.New("key not found")
+	// ErrExists is returned when the key is already in the cache but shouldn't be,
+	// e.g. by CompareAndSwap with zero version.
+	ErrExists = errors.New("key already exists")
+	// ErrVersionMismatch is returned when the version of the key differs from the expected one.
+	ErrVersionMismatch = errors.New("version mismatch")
+	// ErrNotNumeric is returned when the value to increment is not a decimal integer.
+	ErrNotNumeric = errors.New("value is not an integer")
+	// ErrOverflow is returned when the increment result doesn't fit into int64.
+	ErrOverflow = errors.New("increment would overflow")
+	// ErrInvalidKey is returned when the key to store is empty.
+	ErrInvalidKey = errors.New("key must not be empty")
+	// ErrTooLarge is returned when the entry to store is larger than the part of
+	// Configuration.MaxBytes given to its shard, so it could never fit.
+	ErrTooLarge = errors.New("value is too large for the cache")
+	// ErrInvalidTtl is returned when the given TTL or expiration date cannot be applied.
+	ErrInvalidTtl = errors.New("invalid ttl")
+	// ErrLocked is returned when the lock is held by another owner.
+	ErrLocked = errors.New("lock is held by another owner")
+	// ErrNotOwner is returned when the lock to renew or release is not held by the owner.
+	ErrNotOwner = errors.New("lock is not held by the owner")
+	// ErrConflict is returned when a transaction kept conflicting with concurrent writes.
+	ErrConflict = errors.New("transaction conflict")
+	// ErrNamespaceNotFound is returned when the namespace doesn't exist.
+	ErrNamespaceNotFound = errors.New("namespace not found")
+	// ErrNamespaceExists is returned when the namespace to create already exists.
+	ErrNamespaceExists = errors.New("namespace already exists")
+	// ErrInvalidNamespace is returned when the namespace name is not allowed
+	// or the namespace cannot be dropped.
+	ErrInvalidNamespace = errors.New("invalid namespace")
+	// ErrReadOnly is returned by the storages that don't accept changes from this cache,
+	// e.g. by a consensus log on the nodes other than the leader.
+	ErrReadOnly = errors.New("cache is read-only")
+	// ErrUnavailable is wrapped by the errors of the storage failing to record a change,
+	// the change is not applied by the cache. Retrying the change later may succeed.
+	ErrUnavailable = errors.New("storage is unavailable")
+)
+
+// KeyError is returned by the writes of several keys for the key that cannot be written.
+type KeyError struct {
+	Key string
+	Err error
+}
+
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("%v: %q", e.Err, e.Key)
+}
+
+func (e *KeyError) Unwrap() error {
+	return e.Err
+}
+
+// storageError wraps the error of the storage with ErrUnavailable unless it already is
+// a read-only or unavailable error.
+func storageError(err error) error {
+	if errors.Is(err, ErrReadOnly) || errors.Is(err, ErrUnavailable) {
+		return err
+	}
+	return fmt.Errorf("%w: %v", ErrUnavailable, err)
+}
@@ -0,0 +1,196 @@
+package kv
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const DefaultEventBuffer = 256
+
+// ErrSlowSubscriber is returned by Subscription.Err when the subscription was dropped
+// because its buffer overflowed.
+var ErrSlowSubscriber = errors.New("subscriber is too slow, events were dropped")
+
+// EventKind specifies what happened to a key.
+type EventKind int
+
+const (
+	// EventSet means that a value was stored.
+	EventSet EventKind = iota
+	// EventDelete means that a key was removed.
+	EventDelete
+	// EventExpire means that a key was deleted because of its TTL.
+	EventExpire
+	// EventTtl means that the expiration date of a key was changed.
+	EventTtl
+	// EventEvict means that a key was deleted to keep the cache within its size limits.
+	EventEvict
+	// EventSlide means that the sliding expiration of a key was renewed by a read.
+	// It is delivered only to the subscriptions with SubscribeOptions.Slides set.
+	EventSlide
+)
+
+// Event describes a change of a key. TtlBox holds the state of the entry
+// after the change, or the last state for deletions and expirations.
+type Event struct {
+	Kind EventKind
+	Key  string
+	// Time is the moment of the change by the cache clock.
+	Time time.Time
+	TtlBox
+}
+
+// SubscribeOptions defines which events are delivered to a subscription.
+// If Key is set only the events of this key are delivered, otherwise
+// the events of the keys starting with Prefix.
+type SubscribeOptions struct {
+	Key    string
+	Prefix string
+	// Buffer is the number of events that can wait for the subscriber,
+	// DefaultEventBuffer is used if it is not set.
+	Buffer int
+	// Slides enables the delivery of EventSlide.
+	Slides bool
+}
+
+// Subscription receives events from the cache. Events are never blocked
+// by a subscriber: if it doesn't keep up and the buffer overflows,
+// the subscription is dropped, C is closed and Err returns ErrSlowSubscriber.
+type Subscription struct {
+	C <-chan Event
+
+	opts   SubscribeOptions
+	bus    *eventBus
+	mu     sync.Mutex
+	c      chan Event
+	closed bool
+	err    error
+}
+
+// Close stops the delivery of events and closes C.
+func (s *Subscription) Close() {
+	s.bus.unsubscribe(s)
+	s.close(nil)
+}
+
+// Err returns ErrSlowSubscriber if the subscription was dropped, nil otherwise.
+func (s *Subscription) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func (s *Subscription) matches(ev Event) bool {
+	if ev.Kind == EventSlide && !s.opts.Slides {
+		return false
+	}
+	if s.opts.Key != "" {
+		return ev.Key == s.opts.Key
+	}
+	return strings.HasPrefix(ev.Key, s.opts.Prefix)
+}
+
+// deliver sends the event without blocking, returns false if the buffer is full.
+func (s *Subscription) deliver(ev Event) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return true
+	}
+	select {
+	case s.c <- ev:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Subscription) close(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	s.err = err
+	close(s.c)
+}
+
+// eventBus delivers events to subscriptions. The list of subscriptions is replaced
+// on every change, so publishing doesn't take any lock when nobody listens.
+type eventBus struct {
+	mu    sync.Mutex
+	subs  atomic.Value // []*Subscription
+	clock Clock
+}
+
+func newEventBus(clock Clock) *eventBus {
+	b := &eventBus{clock: clock}
+	b.subs.Store([]*Subscription(nil))
+	return b
+}
+
+func (b *eventBus) subscribe(opts SubscribeOptions) *Subscription {
+	if opts.Buffer <= 0 {
+		opts.Buffer = DefaultEventBuffer
+	}
+	c := make(chan Event, opts.Buffer)
+	sub := &Subscription{C: c, c: c, opts: opts, bus: b}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	old := b.subs.Load().([]*Subscription)
+	subs := make([]*Subscription, len(old), len(old)+1)
+	copy(subs, old)
+	b.subs.Store(append(subs, sub))
+	return sub
+}
+
+func (b *eventBus) unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	old := b.subs.Load().([]*Subscription)
+	subs := make([]*Subscription, 0, len(old))
+	for _, s := range old {
+		if s != sub {
+			subs = append(subs, s)
+		}
+	}
+	b.subs.Store(subs)
+}
+
+// closeAll closes every subscription, used when the cache is closed.
+func (b *eventBus) closeAll() {
+	b.mu.Lock()
+	subs := b.subs.Load().([]*Subscription)
+	b.subs.Store([]*Subscription(nil))
+	b.mu.Unlock()
+	for _, sub := range subs {
+		sub.close(nil)
+	}
+}
+
+// publish delivers the event to the matching subscriptions dropping the slow ones.
+// Called with the lock of the key's shard held, so the events of a key are ordered.
+func (b *eventBus) publish(ev Event) {
+	subs := b.subs.Load().([]*Subscription)
+	if len(subs) == 0 {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = b.clock.Now()
+	}
+	for _, sub := range subs {
+		if sub.matches(ev) && !sub.deliver(ev) {
+			b.unsubscribe(sub)
+			sub.close(ErrSlowSubscriber)
+		}
+	}
+}
+
+// Subscribe starts delivering the events of the keys satisfying the options.
+func (c *cache) Subscribe(opts SubscribeOptions) *Subscription {
+	return c.events.subscribe(opts)
+}
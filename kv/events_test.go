@@ -0,0 +1,69 @@
+package kv
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEvents(t *testing.T) {
+	forEachShardCount(t, func(t *testing.T, c Cache, clock *FakeClock) {
+		sub := c.Subscribe(SubscribeOptions{Prefix: "user:"})
+		defer sub.Close()
+		single := c.Subscribe(SubscribeOptions{Key: "user:1"})
+		defer single.Close()
+
+		c.Add("user:1", T{V: []byte("one")})
+		c.AddWithTtl("user:2", T{V: []byte("two")}, time.Second)
+		c.Add("order:1", T{V: []byte("order")})
+		expired := clock.Now().Add(time.Hour)
+		c.SetTtl("user:1", &expired)
+		clock.Advance(2 * time.Second)
+		c.Remove("user:1")
+
+		var got []string
+		for i := 0; i < 5; i++ {
+			ev := <-sub.C
+			got = append(got, fmt.Sprintf("%d %s", ev.Kind, ev.Key))
+		}
+		expected := []string{
+			fmt.Sprintf("%d user:1", EventSet),
+			fmt.Sprintf("%d user:2", EventSet),
+			fmt.Sprintf("%d user:1", EventTtl),
+			fmt.Sprintf("%d user:2", EventExpire),
+			fmt.Sprintf("%d user:1", EventDelete),
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("unexpected events: %v", got)
+		}
+		if len(single.C) != 3 {
+			t.Errorf("expected 3 events of a single key, got %d", len(single.C))
+		}
+	})
+}
+
+func TestSlowSubscriberDropped(t *testing.T) {
+	c := NewCache(Configuration{})
+	sub := c.Subscribe(SubscribeOptions{Buffer: 2})
+	c.Add("1", T{})
+	c.Add("2", T{})
+	c.Add("3", T{})
+
+	for range sub.C {
+	}
+	if sub.Err() != ErrSlowSubscriber {
+		t.Errorf("expected slow subscriber error, got %v", sub.Err())
+	}
+
+	fast := c.Subscribe(SubscribeOptions{})
+	c.Add("4", T{})
+	c.Close(context.Background())
+	if ev, ok := <-fast.C; !ok || ev.Key != "4" {
+		t.Errorf("expected event before close, got %v %v", ev, ok)
+	}
+	if _, ok := <-fast.C; ok || fast.Err() != nil {
+		t.Errorf("expected subscription to be closed without error, got %v", fast.Err())
+	}
+}
@@ -0,0 +1,328 @@
+package kv
+
+import (
+	"container/heap"
+	"container/list"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+// entryOverhead approximates the memory taken by an entry apart from its key and value.
+const entryOverhead = 64
+
+// entrySize estimates the memory taken by the entry, used with Configuration.MaxBytes.
+func entrySize(key string, box TtlBox) int64 {
+	return int64(len(key) + len(box.Content.V) + len(box.Content.ContentType) + entryOverhead)
+}
+
+// limitShards splits the size limits between shards and sets up their eviction policies.
+// The remainders go to the first shards, so the limits of the shards sum up to the limits of the cache.
+func (c *cache) limitShards() {
+	if c.config.MaxEntries <= 0 && c.config.MaxBytes <= 0 {
+		return
+	}
+	if c.config.NewEvictionPolicy == nil {
+		c.config.NewEvictionPolicy = NewLruPolicy
+	}
+	n := len(c.shards)
+	for i, s := range c.shards {
+		s.policy = c.config.NewEvictionPolicy()
+		if c.config.MaxEntries > 0 {
+			s.maxEntries = c.config.MaxEntries / n
+			if i < c.config.MaxEntries%n {
+				s.maxEntries++
+			}
+		}
+		if c.config.MaxBytes > 0 {
+			s.maxBytes = c.config.MaxBytes / int64(n)
+			if int64(i) < c.config.MaxBytes%int64(n) {
+				s.maxBytes++
+			}
+			if s.maxBytes == 0 {
+				// Zero means no limit, a shard this small cannot hold any entry anyway.
+				s.maxBytes = 1
+			}
+		}
+	}
+}
+
+// makeRoom deletes keys from the shard held locked by the caller so that storing
+// the box under the key keeps the shard within its limits. Expired keys are deleted
+// first, then the keys chosen by the eviction policy. An empty key only brings
+// the shard within the limits.
+func (c *cache) makeRoom(s *shard, key string, box TtlBox, now time.Time) {
+	if s.policy == nil {
+		return
+	}
+	size := func() (int, int64) {
+		entries, bytes := len(s.values), s.bytes
+		if key == "" {
+			return entries, bytes
+		}
+		if old, ok := s.values[key]; ok {
+			entries--
+			bytes -= entrySize(key, old)
+		}
+		return entries + 1, bytes + entrySize(key, box)
+	}
+	if !s.overLimit(size()) {
+		return
+	}
+	c.expireLocked(s, now)
+	for s.overLimit(size()) {
+		s.policyMu.Lock()
+		victim, ok := s.policy.Victim()
+		if ok && victim == key {
+			// The old value is replaced anyway, put adds the key back to the policy.
+			s.policy.Remove(key)
+		}
+		s.policyMu.Unlock()
+		if !ok {
+			return
+		}
+		if victim == key {
+			continue
+		}
+		if !c.evictLocked(s, victim) {
+			return
+		}
+	}
+}
+
+// evictLocked deletes the key from the shard held locked by the caller to free space.
+func (c *cache) evictLocked(s *shard, key string) bool {
+	box := s.values[key]
+	if err := c.journal(Change{Kind: ChangeDelete, Key: key, Event: EventEvict}); err != nil {
+		logChangeError(err)
+		return false
+	}
+	s.delete(key)
+	s.markDirty(key)
+	atomic.AddUint64(&c.evictions, 1)
+	c.events.publish(Event{Kind: EventEvict, Key: key, TtlBox: box})
+	return true
+}
+
+// EvictionPolicy chooses the keys to evict when a shard exceeds its size limit.
+// Every shard has its own policy, the calls to it are serialized by the cache.
+type EvictionPolicy interface {
+	// Add is called when a value is stored for the key, including overwrites.
+	Add(key string, box TtlBox)
+	// Access is called when the key is read.
+	Access(key string)
+	// Remove is called when the key is deleted from the cache for any reason.
+	Remove(key string)
+	// Victim returns the key to evict next, false if the policy has no keys.
+	Victim() (string, bool)
+}
+
+// NewLruPolicy evicts the least recently used keys first.
+func NewLruPolicy() EvictionPolicy {
+	return &lruPolicy{order: list.New(), items: make(map[string]*list.Element)}
+}
+
+type lruPolicy struct {
+	// order holds the keys from the most to the least recently used.
+	order *list.List
+	items map[string]*list.Element
+}
+
+func (p *lruPolicy) Add(key string, _ TtlBox) {
+	if e, ok := p.items[key]; ok {
+		p.order.MoveToFront(e)
+		return
+	}
+	p.items[key] = p.order.PushFront(key)
+}
+
+func (p *lruPolicy) Access(key string) {
+	if e, ok := p.items[key]; ok {
+		p.order.MoveToFront(e)
+	}
+}
+
+func (p *lruPolicy) Remove(key string) {
+	if e, ok := p.items[key]; ok {
+		p.order.Remove(e)
+		delete(p.items, key)
+	}
+}
+
+func (p *lruPolicy) Victim() (string, bool) {
+	e := p.order.Back()
+	if e == nil {
+		return "", false
+	}
+	return e.Value.(string), true
+}
+
+// NewLfuPolicy evicts the least frequently used keys first,
+// the least recently used one among the keys used equally often.
+func NewLfuPolicy() EvictionPolicy {
+	return &lfuPolicy{items: make(map[string]*lfuItem)}
+}
+
+type lfuItem struct {
+	key   string
+	count uint64
+	// used is the sequence number of the last use.
+	used  uint64
+	index int
+}
+
+// lfuHeap implements heap.Interface ordering keys by the number of uses and the last use.
+type lfuHeap []*lfuItem
+
+func (h lfuHeap) Len() int { return len(h) }
+
+func (h lfuHeap) Less(i, j int) bool {
+	if h[i].count != h[j].count {
+		return h[i].count < h[j].count
+	}
+	return h[i].used < h[j].used
+}
+
+func (h lfuHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *lfuHeap) Push(x interface{}) {
+	item := x.(*lfuItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *lfuHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+type lfuPolicy struct {
+	heap  lfuHeap
+	items map[string]*lfuItem
+	seq   uint64
+}
+
+func (p *lfuPolicy) Add(key string, _ TtlBox) {
+	if _, ok := p.items[key]; ok {
+		p.Access(key)
+		return
+	}
+	p.seq++
+	item := &lfuItem{key: key, count: 1, used: p.seq}
+	heap.Push(&p.heap, item)
+	p.items[key] = item
+}
+
+func (p *lfuPolicy) Access(key string) {
+	item, ok := p.items[key]
+	if !ok {
+		return
+	}
+	p.seq++
+	item.count++
+	item.used = p.seq
+	heap.Fix(&p.heap, item.index)
+}
+
+func (p *lfuPolicy) Remove(key string) {
+	item, ok := p.items[key]
+	if !ok {
+		return
+	}
+	heap.Remove(&p.heap, item.index)
+	delete(p.items, key)
+}
+
+func (p *lfuPolicy) Victim() (string, bool) {
+	if len(p.heap) == 0 {
+		return "", false
+	}
+	return p.heap[0].key, true
+}
+
+// NewRandomPolicy evicts random keys.
+func NewRandomPolicy() EvictionPolicy {
+	return &randomPolicy{
+		indexes: make(map[string]int),
+		rnd:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+type randomPolicy struct {
+	keys    []string
+	indexes map[string]int
+	rnd     *rand.Rand
+}
+
+func (p *randomPolicy) Add(key string, _ TtlBox) {
+	if _, ok := p.indexes[key]; ok {
+		return
+	}
+	p.indexes[key] = len(p.keys)
+	p.keys = append(p.keys, key)
+}
+
+func (p *randomPolicy) Access(string) {}
+
+func (p *randomPolicy) Remove(key string) {
+	i, ok := p.indexes[key]
+	if !ok {
+		return
+	}
+	last := len(p.keys) - 1
+	p.keys[i] = p.keys[last]
+	p.indexes[p.keys[i]] = i
+	p.keys = p.keys[:last]
+	delete(p.indexes, key)
+}
+
+func (p *randomPolicy) Victim() (string, bool) {
+	if len(p.keys) == 0 {
+		return "", false
+	}
+	return p.keys[p.rnd.Intn(len(p.keys))], true
+}
+
+// NewTtlPolicy evicts the keys that expire soonest first. The keys without
+// expiration date are evicted only when there are no others, least recently used first.
+func NewTtlPolicy() EvictionPolicy {
+	return &ttlPolicy{expiry: newExpiryIndex(), lru: NewLruPolicy()}
+}
+
+type ttlPolicy struct {
+	expiry *expiryIndex
+	lru    EvictionPolicy
+}
+
+func (p *ttlPolicy) Add(key string, box TtlBox) {
+	p.expiry.set(key, box.Expired)
+	if box.Expired != nil {
+		p.lru.Remove(key)
+	} else {
+		p.lru.Add(key, box)
+	}
+}
+
+func (p *ttlPolicy) Access(key string) {
+	p.lru.Access(key)
+}
+
+func (p *ttlPolicy) Remove(key string) {
+	p.expiry.remove(key)
+	p.lru.Remove(key)
+}
+
+func (p *ttlPolicy) Victim() (string, bool) {
+	if key, ok := p.expiry.first(); ok {
+		return key, true
+	}
+	return p.lru.Victim()
+}
@@ -0,0 +1,149 @@
+package kv
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestEvictionPolicies(t *testing.T) {
+	v := T{V: []byte("v")}
+	tests := []struct {
+		name    string
+		policy  func() EvictionPolicy
+		prepare func(c Cache)
+		evicted string
+	}{
+		{"lru", NewLruPolicy, func(c Cache) {
+			c.Value("a")
+		}, "b"},
+		{"lfu", NewLfuPolicy, func(c Cache) {
+			c.Value("a")
+			c.Value("a")
+			c.Value("b")
+			c.Value("c")
+		}, "b"},
+		{"ttl", NewTtlPolicy, func(c Cache) {
+			c.AddWithTtl("a", v, time.Hour)
+			c.AddWithTtl("b", v, time.Minute)
+		}, "b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewFakeClock(time.Now())
+			c := NewCache(Configuration{ShardCount: 1, MaxEntries: 3, NewEvictionPolicy: tt.policy, Clock: clock})
+			defer c.Close(context.Background())
+			sub := c.Subscribe(SubscribeOptions{})
+			c.Add("a", v)
+			c.Add("b", v)
+			c.Add("c", v)
+			tt.prepare(c)
+			c.Add("d", v)
+
+			if _, ok := c.Value(tt.evicted); ok {
+				t.Errorf("expected %s to be evicted", tt.evicted)
+			}
+			if n := len(c.ListAll()); n != 3 {
+				t.Errorf("expected 3 values, got %d", n)
+			}
+			if s := c.Stats(); s.Evictions != 1 || s.Expirations != 0 {
+				t.Errorf("expected a single eviction, got %+v", s)
+			}
+			var evicted []string
+			for len(sub.C) > 0 {
+				if ev := <-sub.C; ev.Kind == EventEvict {
+					evicted = append(evicted, ev.Key)
+				}
+			}
+			if len(evicted) != 1 || evicted[0] != tt.evicted {
+				t.Errorf("expected evict event for %s, got %v", tt.evicted, evicted)
+			}
+		})
+	}
+}
+
+func TestEvictionLimits(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	c := NewCache(Configuration{ShardCount: 1, MaxEntries: 2, CleanInterval: time.Hour, Clock: clock})
+	defer c.Close(context.Background())
+	c.AddWithTtl("a", T{V: []byte("v")}, time.Second)
+	c.Add("b", T{V: []byte("v")})
+	c.Add("b", T{V: []byte("w")})
+	if s := c.Stats(); s.Evictions != 0 {
+		t.Errorf("expected overwrite not to evict, got %+v", s)
+	}
+	clock.Advance(2 * time.Second)
+	c.Add("c", T{V: []byte("v")})
+	if s := c.Stats(); s.Evictions != 0 || s.Expirations != 1 {
+		t.Errorf("expected the expired key to be deleted instead of evicting, got %+v", s)
+	}
+	if _, ok := c.Value("b"); !ok {
+		t.Error("expected b to stay")
+	}
+
+	value := T{V: make([]byte, 10)}
+	m := NewCache(Configuration{ShardCount: 1, MaxBytes: 2 * entrySize("k1", TtlBox{Content: value})})
+	defer m.Close(context.Background())
+	m.Add("k1", value)
+	m.Add("k2", value)
+	m.Add("k3", value)
+	if _, ok := m.Value("k1"); ok {
+		t.Error("expected k1 to be evicted by memory limit")
+	}
+	if n := len(m.ListAll()); n != 2 {
+		t.Errorf("expected 2 values, got %d", n)
+	}
+}
+
+func TestEvictionLimitsBelowShardCount(t *testing.T) {
+	for _, limit := range []int{5, 40} {
+		c := NewCache(Configuration{ShardCount: 32, MaxEntries: limit})
+		total := 0
+		for _, s := range c.(*cache).shards {
+			total += s.maxEntries
+		}
+		if total != limit {
+			t.Errorf("expected the shard limits to sum up to %d, got %d", limit, total)
+		}
+		for i := 0; i < 1000; i++ {
+			c.Add(fmt.Sprintf("key%d", i), T{V: []byte("v")})
+		}
+		if n := len(c.ListAll()); n != limit {
+			t.Errorf("expected %d values, got %d", limit, n)
+		}
+		c.Close(context.Background())
+	}
+}
+
+// A value that doesn't fit into its shard is rejected instead of evicting the other keys.
+func TestEvictionTooLarge(t *testing.T) {
+	c := NewCache(Configuration{ShardCount: 32, MaxBytes: 1 << 20})
+	defer c.Close(context.Background())
+	for i := 0; i < 200; i++ {
+		if err := c.Add(fmt.Sprintf("key%d", i), T{V: make([]byte, 100)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.Add("large", T{V: make([]byte, 100<<10)}); err != ErrTooLarge {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+	if _, err := c.Set("large", T{V: make([]byte, 100<<10)}, 0, WriteAlways); err != ErrTooLarge {
+		t.Errorf("expected ErrTooLarge from Set, got %v", err)
+	}
+	if _, ok := c.Value("large"); ok {
+		t.Error("expected the large value not to be stored")
+	}
+	if s := c.Stats(); s.Evictions != 0 || len(c.ListAll()) != 200 {
+		t.Errorf("expected nothing to be evicted, got %+v", s)
+	}
+	// A value that fits into the shard is stored evicting other keys if needed.
+	if err := c.Add("fits", T{V: make([]byte, 16<<10)}); err != nil {
+		t.Errorf("expected the value to be stored, got %v", err)
+	}
+	for _, s := range c.(*cache).shards {
+		if s.bytes > s.maxBytes {
+			t.Errorf("expected the shard within %d bytes, got %d", s.maxBytes, s.bytes)
+		}
+	}
+}
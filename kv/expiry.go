@@ -0,0 +1,98 @@
+package kv
+
+import (
+	"container/heap"
+	"time"
+)
+
+// expiryItem is an entry of the expiry index.
+type expiryItem struct {
+	key     string
+	expired time.Time
+	index   int
+}
+
+// expiryHeap implements heap.Interface ordering keys by expiration time.
+type expiryHeap []*expiryItem
+
+func (h expiryHeap) Len() int           { return len(h) }
+func (h expiryHeap) Less(i, j int) bool { return h[i].expired.Before(h[j].expired) }
+
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *expiryHeap) Push(x interface{}) {
+	item := x.(*expiryItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *expiryHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+// expiryIndex keeps the keys having TTL ordered by expiration time, so the cleaner
+// finds expired keys without scanning the whole shard. Not safe for concurrent use.
+type expiryIndex struct {
+	heap  expiryHeap
+	items map[string]*expiryItem
+}
+
+func newExpiryIndex() *expiryIndex {
+	return &expiryIndex{
+		items: make(map[string]*expiryItem),
+	}
+}
+
+// set updates the expiration time of the key. Nil removes the key from the index.
+func (x *expiryIndex) set(key string, expired *time.Time) {
+	if expired == nil {
+		x.remove(key)
+		return
+	}
+	if item, ok := x.items[key]; ok {
+		item.expired = *expired
+		heap.Fix(&x.heap, item.index)
+		return
+	}
+	item := &expiryItem{key: key, expired: *expired}
+	heap.Push(&x.heap, item)
+	x.items[key] = item
+}
+
+// remove deletes the key from the index if it is there.
+func (x *expiryIndex) remove(key string) {
+	item, ok := x.items[key]
+	if !ok {
+		return
+	}
+	heap.Remove(&x.heap, item.index)
+	delete(x.items, key)
+}
+
+// popExpired removes from the index and returns the keys expired by the given moment.
+func (x *expiryIndex) popExpired(now time.Time) []string {
+	var keys []string
+	for len(x.heap) > 0 && now.After(x.heap[0].expired) {
+		item := heap.Pop(&x.heap).(*expiryItem)
+		delete(x.items, item.key)
+		keys = append(keys, item.key)
+	}
+	return keys
+}
+
+// first returns the key that expires soonest, false if the index is empty.
+func (x *expiryIndex) first() (string, bool) {
+	if len(x.heap) == 0 {
+		return "", false
+	}
+	return x.heap[0].key, true
+}
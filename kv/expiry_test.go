@@ -0,0 +1,37 @@
+package kv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExpiryIndex(t *testing.T) {
+	now := time.Now()
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	x := newExpiryIndex()
+	x.set("3", at(3*time.Second))
+	x.set("1", at(1*time.Second))
+	x.set("2", at(5*time.Second))
+	x.set("4", at(4*time.Second))
+	x.set("gone", at(time.Second))
+	x.remove("gone")
+	x.set("2", at(2*time.Second))
+	x.set("4", nil)
+
+	if keys := x.popExpired(now); len(keys) != 0 {
+		t.Errorf("expected nothing expired, got %v", keys)
+	}
+	if keys := x.popExpired(now.Add(2500 * time.Millisecond)); !reflect.DeepEqual(keys, []string{"1", "2"}) {
+		t.Errorf("unexpected expired keys: %v", keys)
+	}
+	if keys := x.popExpired(now.Add(time.Hour)); !reflect.DeepEqual(keys, []string{"3"}) {
+		t.Errorf("unexpected expired keys: %v", keys)
+	}
+	if len(x.items) != 0 || len(x.heap) != 0 {
+		t.Errorf("expected empty index, got %v", x.items)
+	}
+}
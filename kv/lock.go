@@ -0,0 +1,107 @@
+package kv
+
+import "time"
+
+// LockContentType marks the entries holding locks, their value is the owner of the lock.
+const LockContentType = "application/vnd.kv-ttl.lock"
+
+// Lock acquires the lock with the name for the owner until ttl passes and returns
+// the fencing token of the lock. Tokens grow with every acquisition, so the holders
+// of expired locks can be told apart by the resources they access.
+// If the owner already holds the lock, its ttl is renewed and the same token is returned.
+// The lock is an ordinary entry with TTL deleted by the cleaner once it expires,
+// ErrLocked is returned if the key is taken by another owner or by a value.
+func (c *cache) Lock(name, owner string, ttl time.Duration) (uint64, error) {
+	if ttl <= 0 {
+		return 0, ErrInvalidTtl
+	}
+	s := c.shardFor(name)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := c.clock.Now()
+	if box, ok := s.values[name]; ok && !box.IsExpired(now) {
+		if !isLockOf(box, owner) {
+			return 0, ErrLocked
+		}
+		return box.Version, c.renewLocked(s, name, box, ttl, now)
+	}
+	expired := now.Add(ttl)
+	box := TtlBox{
+		CreatedAt: now,
+		Expired:   &expired,
+		Content:   T{V: []byte(owner), ContentType: LockContentType},
+		Version:   c.nextVersion(),
+	}
+	if err := c.journal(Change{Kind: ChangeSet, Key: name, Box: box}); err != nil {
+		return 0, err
+	}
+	c.makeRoom(s, name, box, now)
+	s.put(name, box)
+	s.markDirty(name)
+	c.events.publish(Event{Kind: EventSet, Key: name, TtlBox: box})
+	return box.Version, nil
+}
+
+// Renew extends the lock held by the owner with the token until ttl passes.
+// Returns ErrNotFound if the lock has expired and ErrNotOwner if it is held by another owner.
+func (c *cache) Renew(name, owner string, token uint64, ttl time.Duration) error {
+	if ttl <= 0 {
+		return ErrInvalidTtl
+	}
+	s := c.shardFor(name)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := c.clock.Now()
+	box, err := heldLock(s, name, owner, token, now)
+	if err != nil {
+		return err
+	}
+	return c.renewLocked(s, name, box, ttl, now)
+}
+
+// Unlock releases the lock held by the owner with the token, see Renew for the errors.
+func (c *cache) Unlock(name, owner string, token uint64) error {
+	s := c.shardFor(name)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	box, err := heldLock(s, name, owner, token, c.clock.Now())
+	if err != nil {
+		return err
+	}
+	if err := c.journal(Change{Kind: ChangeDelete, Key: name}); err != nil {
+		return err
+	}
+	s.delete(name)
+	s.markDirty(name)
+	c.events.publish(Event{Kind: EventDelete, Key: name, TtlBox: box})
+	return nil
+}
+
+// renewLocked moves the expiration date of the lock keeping its version, which is the fencing token.
+func (c *cache) renewLocked(s *shard, name string, box TtlBox, ttl time.Duration, now time.Time) error {
+	expired := now.Add(ttl)
+	box.Expired = &expired
+	if err := c.journal(Change{Kind: ChangeSet, Key: name, Box: box, Event: EventTtl}); err != nil {
+		return err
+	}
+	s.put(name, box)
+	s.markDirty(name)
+	c.events.publish(Event{Kind: EventTtl, Key: name, TtlBox: box})
+	return nil
+}
+
+// heldLock returns the lock if it is held by the owner with the token.
+func heldLock(s *shard, name, owner string, token uint64, now time.Time) (TtlBox, error) {
+	box, ok := s.values[name]
+	if !ok || box.IsExpired(now) {
+		return TtlBox{}, ErrNotFound
+	}
+	if !isLockOf(box, owner) || box.Version != token {
+		return TtlBox{}, ErrNotOwner
+	}
+	return box, nil
+}
+
+func isLockOf(box TtlBox, owner string) bool {
+	return box.Content.ContentType == LockContentType && string(box.Content.V) == owner
+}
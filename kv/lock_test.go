@@ -0,0 +1,55 @@
+package kv
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLock(t *testing.T) {
+	forEachShardCount(t, func(t *testing.T, c Cache, clock *FakeClock) {
+		token, err := c.Lock("job", "a", time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.Lock("job", "b", time.Second); err != ErrLocked {
+			t.Errorf("expected lock to be held, got %v", err)
+		}
+		if again, err := c.Lock("job", "a", time.Second); err != nil || again != token {
+			t.Errorf("expected the owner to get the same token, got %d %v", again, err)
+		}
+		if err := c.Unlock("job", "b", token); err != ErrNotOwner {
+			t.Errorf("expected unlock by another owner to fail, got %v", err)
+		}
+		if err := c.Renew("job", "a", token+1, time.Second); err != ErrNotOwner {
+			t.Errorf("expected renew with a wrong token to fail, got %v", err)
+		}
+		clock.Advance(800 * time.Millisecond)
+		if err := c.Renew("job", "a", token, time.Second); err != nil {
+			t.Fatal(err)
+		}
+		clock.Advance(800 * time.Millisecond)
+		if box, ok := c.Entry("job"); !ok || box.Version != token {
+			t.Errorf("expected renewed lock to keep the token, got %v %v", box.Version, ok)
+		}
+
+		clock.Advance(time.Second)
+		if _, ok := c.Entry("job"); ok {
+			t.Error("expected the cleaner to delete the expired lock")
+		}
+		if err := c.Unlock("job", "a", token); err != ErrNotFound {
+			t.Errorf("expected expired lock to be not found, got %v", err)
+		}
+		next, err := c.Lock("job", "b", time.Second)
+		if err != nil || next <= token {
+			t.Errorf("expected a greater token, got %d %v", next, err)
+		}
+		if err := c.Unlock("job", "b", next); err != nil {
+			t.Fatal(err)
+		}
+
+		c.Add("value", T{V: []byte("a")})
+		if _, err := c.Lock("value", "a", time.Second); err != ErrLocked {
+			t.Errorf("expected a value not to be taken for a lock, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,254 @@
+package kv
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultNamespace is the namespace used when none is specified. It always exists.
+const DefaultNamespace = "default"
+
+var namespaceName = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// NamespaceOptions configures a namespace, see the Configuration fields with the same names.
+type NamespaceOptions struct {
+	DefaultTtl time.Duration
+	MaxEntries int
+	MaxBytes   int64
+}
+
+// NamespaceInfo describes an existing namespace.
+type NamespaceInfo struct {
+	Name string
+	NamespaceOptions
+}
+
+// NamespaceEvent reports that a namespace was created or dropped.
+type NamespaceEvent struct {
+	NamespaceInfo
+	// Cache is the cache of the created namespace, nil for the dropped ones.
+	Cache   Cache
+	Dropped bool
+}
+
+// NamespaceStorage is a storage able to keep every namespace separately.
+// The storage itself keeps the default namespace.
+type NamespaceStorage interface {
+	Storage
+	// Namespace returns the storage of the namespace.
+	Namespace(name string) Storage
+	// RestoreNamespaces reads the options of the namespaces saved before.
+	RestoreNamespaces() (map[string]NamespaceOptions, error)
+	// SaveNamespaces replaces the saved namespaces.
+	SaveNamespaces(namespaces map[string]NamespaceOptions) error
+	// DropNamespace deletes the data of the namespace.
+	DropNamespace(name string) error
+}
+
+// Namespaces holds independent caches by name, so keys of different namespaces don't collide.
+// If the storage of the base configuration implements NamespaceStorage, every namespace
+// is persisted separately and the namespaces are restored on start.
+type Namespaces struct {
+	base    Configuration
+	storage NamespaceStorage
+
+	mu       sync.RWMutex
+	caches   map[string]*namespace
+	watchers map[int]func(NamespaceEvent)
+	watchID  int
+}
+
+type namespace struct {
+	opts  NamespaceOptions
+	cache Cache
+}
+
+// NewNamespaces creates the namespaces served by the given cache as the default one.
+// New namespaces are configured like base with their own options.
+func NewNamespaces(def Cache, base Configuration) *Namespaces {
+	n := &Namespaces{
+		base:     base,
+		watchers: make(map[int]func(NamespaceEvent)),
+		caches: map[string]*namespace{
+			DefaultNamespace: {
+				opts:  NamespaceOptions{DefaultTtl: base.DefaultTtl, MaxEntries: base.MaxEntries, MaxBytes: base.MaxBytes},
+				cache: def,
+			},
+		},
+	}
+	ns, ok := base.Storage.(NamespaceStorage)
+	if !ok {
+		return n
+	}
+	n.storage = ns
+	saved, err := ns.RestoreNamespaces()
+	if err != nil {
+		log.Println(err)
+	}
+	for name, opts := range saved {
+		if name != DefaultNamespace && namespaceName.MatchString(name) {
+			n.caches[name] = &namespace{opts: opts, cache: NewCache(n.config(name, opts))}
+		}
+	}
+	return n
+}
+
+// config returns the configuration of the namespace cache.
+func (n *Namespaces) config(name string, opts NamespaceOptions) Configuration {
+	config := n.base
+	config.DefaultTtl = opts.DefaultTtl
+	config.MaxEntries = opts.MaxEntries
+	config.MaxBytes = opts.MaxBytes
+	config.Storage = nil
+	if n.storage != nil {
+		config.Storage = n.storage.Namespace(name)
+	}
+	return config
+}
+
+// Cache returns the cache of the namespace, the empty name stands for DefaultNamespace.
+func (n *Namespaces) Cache(name string) (Cache, error) {
+	if name == "" {
+		name = DefaultNamespace
+	}
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	ns, ok := n.caches[name]
+	if !ok {
+		return nil, ErrNamespaceNotFound
+	}
+	return ns.cache, nil
+}
+
+// Create adds an empty namespace. Names may contain up to 64 letters, digits, '_' and '-'.
+func (n *Namespaces) Create(name string, opts NamespaceOptions) error {
+	if !namespaceName.MatchString(name) {
+		return ErrInvalidNamespace
+	}
+	if opts.DefaultTtl < 0 {
+		return ErrInvalidTtl
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if _, ok := n.caches[name]; ok {
+		return ErrNamespaceExists
+	}
+	ns := &namespace{opts: opts, cache: NewCache(n.config(name, opts))}
+	n.caches[name] = ns
+	if err := n.save(); err != nil {
+		delete(n.caches, name)
+		if cErr := ns.cache.Close(context.Background()); cErr != nil {
+			log.Println(cErr)
+		}
+		return err
+	}
+	n.notify(NamespaceEvent{NamespaceInfo: NamespaceInfo{Name: name, NamespaceOptions: opts}, Cache: ns.cache})
+	return nil
+}
+
+// List returns the existing namespaces ordered by name.
+func (n *Namespaces) List() []NamespaceInfo {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	infos := make([]NamespaceInfo, 0, len(n.caches))
+	for name, ns := range n.caches {
+		infos = append(infos, NamespaceInfo{Name: name, NamespaceOptions: ns.opts})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// Drop closes the cache of the namespace and deletes its data from the storage.
+// The default namespace cannot be dropped.
+func (n *Namespaces) Drop(ctx context.Context, name string) error {
+	if name == DefaultNamespace {
+		return ErrInvalidNamespace
+	}
+	n.mu.Lock()
+	ns, ok := n.caches[name]
+	if !ok {
+		n.mu.Unlock()
+		return ErrNamespaceNotFound
+	}
+	delete(n.caches, name)
+	err := n.save()
+	if err == nil {
+		n.notify(NamespaceEvent{NamespaceInfo: NamespaceInfo{Name: name, NamespaceOptions: ns.opts}, Dropped: true})
+	}
+	n.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := ns.cache.Close(ctx); err != nil {
+		log.Println(err)
+	}
+	if n.storage != nil {
+		return n.storage.DropNamespace(name)
+	}
+	return nil
+}
+
+// Watch calls fn for every existing namespace as if it was just created, then for every namespace
+// created or dropped until the returned function is called. fn is called with the namespaces
+// locked, so it must return quickly and must not call them.
+func (n *Namespaces) Watch(fn func(NamespaceEvent)) func() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	names := make([]string, 0, len(n.caches))
+	for name := range n.caches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ns := n.caches[name]
+		fn(NamespaceEvent{NamespaceInfo: NamespaceInfo{Name: name, NamespaceOptions: ns.opts}, Cache: ns.cache})
+	}
+	n.watchID++
+	id := n.watchID
+	n.watchers[id] = fn
+	return func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.watchers, id)
+	}
+}
+
+// notify passes the event to the watchers. Must be called with the lock held.
+func (n *Namespaces) notify(ev NamespaceEvent) {
+	for _, fn := range n.watchers {
+		fn(ev)
+	}
+}
+
+// Close closes the caches of all the namespaces, see Cache.Close.
+// The first error is returned.
+func (n *Namespaces) Close(ctx context.Context) error {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	var err error
+	for _, ns := range n.caches {
+		if cErr := ns.cache.Close(ctx); err == nil {
+			err = cErr
+		}
+	}
+	return err
+}
+
+// save passes the namespaces except the default one to the storage.
+// Must be called with the lock held.
+func (n *Namespaces) save() error {
+	if n.storage == nil {
+		return nil
+	}
+	saved := make(map[string]NamespaceOptions, len(n.caches))
+	for name, ns := range n.caches {
+		if name != DefaultNamespace {
+			saved[name] = ns.opts
+		}
+	}
+	return n.storage.SaveNamespaces(saved)
+}
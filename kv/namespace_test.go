@@ -0,0 +1,65 @@
+package kv
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNamespaces(t *testing.T) {
+	clock := NewFakeClock(time.Now())
+	def := NewCache(Configuration{Clock: clock})
+	n := NewNamespaces(def, Configuration{Clock: clock, ShardCount: 1})
+	defer n.Close(context.Background())
+
+	if err := n.Create("sessions", NamespaceOptions{DefaultTtl: time.Minute, MaxEntries: 1}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"sessions", DefaultNamespace} {
+		if err := n.Create(name, NamespaceOptions{}); err != ErrNamespaceExists {
+			t.Errorf("expected %s to exist, got %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "a/b", "../x"} {
+		if err := n.Create(name, NamespaceOptions{}); err != ErrInvalidNamespace {
+			t.Errorf("expected %q to be invalid, got %v", name, err)
+		}
+	}
+
+	sessions, err := n.Cache("sessions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, _ := n.Cache(""); c != def {
+		t.Error("expected the empty name to stand for the default namespace")
+	}
+	def.Add("key", T{V: []byte("default")})
+	sessions.Add("key", T{V: []byte("session")})
+	if v, _ := def.Value("key"); string(v.V) != "default" {
+		t.Errorf("expected namespaces not to collide, got %s", v.V)
+	}
+	if box, _ := sessions.Entry("key"); box.Expired == nil || !box.Expired.Equal(clock.Now().Add(time.Minute)) {
+		t.Errorf("expected default ttl to be applied, got %v", box.Expired)
+	}
+	sessions.Add("other", T{V: []byte("session")})
+	if len(sessions.ListAll()) != 1 {
+		t.Error("expected the size limit of the namespace to be applied")
+	}
+
+	infos := n.List()
+	if len(infos) != 2 || infos[0].Name != DefaultNamespace || infos[1].Name != "sessions" || infos[1].MaxEntries != 1 {
+		t.Errorf("unexpected namespaces %v", infos)
+	}
+	if err := n.Drop(context.Background(), DefaultNamespace); err != ErrInvalidNamespace {
+		t.Errorf("expected the default namespace not to be dropped, got %v", err)
+	}
+	if err := n.Drop(context.Background(), "sessions"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := n.Cache("sessions"); err != ErrNamespaceNotFound {
+		t.Errorf("expected namespace to be dropped, got %v", err)
+	}
+	if err := n.Drop(context.Background(), "sessions"); err != ErrNamespaceNotFound {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package kv
+
+import (
+	"log"
+	"sync/atomic"
+)
+
+// Replica is implemented by the caches created by NewCache. It repeats the changes
+// of another cache and is used only to replicate the data, type-assert the Cache to it.
+type Replica interface {
+	Load(entries map[string]TtlBox)
+	Apply(ev Event)
+	ApplyChanges(changes ...Change)
+	Copy() map[string]TtlBox
+}
+
+// Load replaces the data of the cache with the entries copied from another cache,
+// keeping their versions. Used by replication followers to apply the snapshot of the leader.
+// Events are published for the keys whose entries change.
+func (c *cache) Load(entries map[string]TtlBox) {
+	c.txMu.RLock()
+	defer c.txMu.RUnlock()
+	if box, ok := entries[versionKey]; ok {
+		c.seeVersion(box.Version)
+	}
+	for i, s := range c.shards {
+		s.mu.Lock()
+		for k, old := range s.values {
+			if _, ok := entries[k]; !ok {
+				c.applyLocked(s, Event{Kind: EventDelete, Key: k, TtlBox: old})
+			}
+		}
+		for k, box := range entries {
+			if k == versionKey || c.shardIndex(k) != i {
+				continue
+			}
+			if old, ok := s.values[k]; !ok || old.Version != box.Version || !sameExpiration(old, box) {
+				c.applyLocked(s, Event{Kind: EventSet, Key: k, TtlBox: box})
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Apply repeats the change published by another cache, keeping the version of the entry.
+// Used by replication followers to apply the changes streamed from the leader.
+// Changes older than the stored entry are ignored, so events received twice do no harm.
+func (c *cache) Apply(ev Event) {
+	s := c.shardFor(ev.Key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if old, ok := s.values[ev.Key]; ok && old.Version > ev.Version {
+		return
+	}
+	c.applyLocked(s, ev)
+}
+
+// applyLocked stores or deletes the entry of the event in the shard held locked by the caller
+// and publishes the event. Nothing is evicted, the evictions of the other cache are applied instead.
+func (c *cache) applyLocked(s *shard, ev Event) {
+	switch ev.Kind {
+	case EventSet, EventTtl, EventSlide:
+		if err := c.record(Change{Kind: ChangeSet, Key: ev.Key, Box: ev.TtlBox}); err != nil {
+			log.Println(err)
+			return
+		}
+		s.put(ev.Key, ev.TtlBox)
+		c.seeVersion(ev.Version)
+	default:
+		if _, ok := s.values[ev.Key]; !ok {
+			return
+		}
+		if err := c.record(Change{Kind: ChangeDelete, Key: ev.Key}); err != nil {
+			log.Println(err)
+			return
+		}
+		s.delete(ev.Key)
+	}
+	s.markDirty(ev.Key)
+	ev.Time = c.clock.Now()
+	c.events.publish(ev)
+}
+
+// sameExpiration reports whether the boxes expire at the same moment.
+func sameExpiration(a, b TtlBox) bool {
+	if a.Expired == nil || b.Expired == nil {
+		return a.Expired == b.Expired
+	}
+	return a.Expired.Equal(*b.Expired)
+}
+
+// ApplyChanges applies the changes already recorded by the storage in the given order,
+// e.g. the committed entries of a consensus log, without journaling them again.
+// The changes are applied all together, like the writes of a transaction.
+// The events of the changes have the kinds published by the cache that made them.
+func (c *cache) ApplyChanges(changes ...Change) {
+	keys := make([]string, len(changes))
+	for i, ch := range changes {
+		keys[i] = ch.Key
+	}
+	c.txMu.RLock()
+	defer c.txMu.RUnlock()
+	defer c.lockShards(keys)()
+	for _, ch := range changes {
+		if ch.Key == versionKey {
+			c.seeVersion(ch.Box.Version)
+			continue
+		}
+		s := c.shardFor(ch.Key)
+		if ch.Kind == ChangeDelete {
+			old, ok := s.values[ch.Key]
+			if !ok {
+				continue
+			}
+			s.delete(ch.Key)
+			s.markDirty(ch.Key)
+			c.events.publish(Event{Kind: ch.event(), Key: ch.Key, TtlBox: old})
+			continue
+		}
+		s.put(ch.Key, ch.Box)
+		s.markDirty(ch.Key)
+		c.seeVersion(ch.Box.Version)
+		c.events.publish(Event{Kind: ch.event(), Key: ch.Key, TtlBox: ch.Box})
+	}
+}
+
+// Copy returns all the entries of the cache including the expired ones and the greatest
+// version under the empty key. The shards are locked all at once, so the copy contains
+// every change journaled before the call.
+func (c *cache) Copy() map[string]TtlBox {
+	for _, s := range c.shards {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+	}
+	entries := make(map[string]TtlBox)
+	for _, s := range c.shards {
+		for k, box := range s.values {
+			entries[k] = box
+		}
+	}
+	entries[versionKey] = c.versionBox()
+	return entries
+}
+
+// seeVersion makes the versions assigned later greater than the version of an applied change.
+func (c *cache) seeVersion(v uint64) {
+	for {
+		version := atomic.LoadUint64(&c.version)
+		if v <= version || atomic.CompareAndSwapUint64(&c.version, version, v) {
+			return
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package kv
+
+import (
+	"context"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	c := NewCache(Configuration{})
+	defer c.Close(context.Background())
+	r := c.(Replica)
+	r.Load(map[string]TtlBox{
+		"a": {Content: T{V: []byte("a")}, Version: 10},
+		"b": {Content: T{V: []byte("b")}, Version: 11},
+	})
+	r.Apply(Event{Kind: EventSet, Key: "a", TtlBox: TtlBox{Content: T{V: []byte("old")}, Version: 9}})
+	if box, _ := c.Entry("a"); string(box.Content.V) != "a" || box.Version != 10 {
+		t.Errorf("expected older change to be ignored, got %v", box)
+	}
+	r.Apply(Event{Kind: EventSet, Key: "a", TtlBox: TtlBox{Content: T{V: []byte("new")}, Version: 12}})
+	r.Apply(Event{Kind: EventExpire, Key: "b", TtlBox: TtlBox{Version: 11}})
+	if box, _ := c.Entry("a"); string(box.Content.V) != "new" || box.Version != 12 {
+		t.Errorf("expected the version of the change to be kept, got %v", box)
+	}
+	if _, ok := c.Value("b"); ok {
+		t.Error("expected b to be deleted")
+	}
+	c.Add("c", T{})
+	if box, _ := c.Entry("c"); box.Version <= 12 {
+		t.Errorf("expected new versions to follow the applied ones, got %d", box.Version)
+	}
+
+	r.Load(map[string]TtlBox{"d": {Version: 1}})
+	if n := len(c.ListAll()); n != 1 {
+		t.Errorf("expected snapshot to replace the data, got %d values", n)
+	}
+}
+
+func TestApplyChangesEvents(t *testing.T) {
+	c := NewCache(Configuration{})
+	defer c.Close(context.Background())
+	sub := c.Subscribe(SubscribeOptions{Slides: true})
+	defer sub.Close()
+	box := TtlBox{Content: T{V: []byte("v")}, Version: 1}
+	c.(Replica).ApplyChanges(
+		Change{Kind: ChangeSet, Key: "a", Box: box},
+		Change{Kind: ChangeSet, Key: "a", Box: box, Event: EventTtl},
+		Change{Kind: ChangeSet, Key: "a", Box: box, Event: EventSlide},
+		Change{Kind: ChangeDelete, Key: "a", Event: EventExpire},
+		Change{Kind: ChangeSet, Key: "b", Box: box},
+		Change{Kind: ChangeDelete, Key: "b", Event: EventEvict},
+		Change{Kind: ChangeSet, Key: "c", Box: box},
+		Change{Kind: ChangeDelete, Key: "c"},
+	)
+	expected := []EventKind{EventSet, EventTtl, EventSlide, EventExpire, EventSet, EventEvict, EventSet, EventDelete}
+	for i, kind := range expected {
+		if ev := <-sub.C; ev.Kind != kind {
+			t.Errorf("expected event %d to be %d, got %d", i, kind, ev.Kind)
+		}
+	}
+}
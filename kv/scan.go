@@ -0,0 +1,128 @@
+package kv
+
+import (
+	"container/heap"
+	"sort"
+	"strings"
+	"time"
+)
+
+const DefaultScanCount = 100
+
+// ScanOptions defines which keys are returned by Cache.Scan.
+type ScanOptions struct {
+	// Cursor is the key returned as next by the previous call, empty to start from the beginning.
+	Cursor string
+	// Count limits the number of returned items, DefaultScanCount is used if it is not set.
+	Count int
+	// Prefix filters the keys that start with it.
+	Prefix string
+	// Match filters the keys by a glob pattern: '*' matches any sequence of characters, '?' - any single one.
+	Match string
+}
+
+// Item is a key-value pair returned by Cache.Scan.
+type Item struct {
+	Key string
+	TtlBox
+}
+
+// Scan returns the pairs in ascending order of keys starting after the cursor.
+// The returned next cursor is passed to the following call, it is empty when
+// the end is reached. Shards are locked one at a time, so the pairs changed
+// during the walk may or may not be returned. Expired pairs are skipped.
+func (c *cache) Scan(opts ScanOptions) ([]Item, string) {
+	if opts.Count <= 0 {
+		opts.Count = DefaultScanCount
+	}
+	now := c.clock.Now()
+	items := make([]Item, 0)
+	for _, s := range c.shards {
+		items = append(items, s.scan(opts, now)...)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Key < items[j].Key })
+	if len(items) <= opts.Count {
+		return items, ""
+	}
+	items = items[:opts.Count]
+	return items, items[len(items)-1].Key
+}
+
+// scan returns up to opts.Count+1 first items of the shard satisfying the options.
+// The extra item tells the caller whether there is anything after the page.
+// The first keys are kept in a heap, so only they are sorted.
+func (s *shard) scan(opts ScanOptions, now time.Time) []Item {
+	limit := opts.Count + 1
+	s.mu.RLock()
+	keys := make(keyHeap, 0, limit)
+	for k, v := range s.values {
+		if k <= opts.Cursor || !matchKey(k, opts) || v.IsExpired(now) {
+			continue
+		}
+		if len(keys) < limit {
+			heap.Push(&keys, k)
+		} else if k < keys[0] {
+			keys[0] = k
+			heap.Fix(&keys, 0)
+		}
+	}
+	sort.Strings(keys)
+	items := make([]Item, len(keys))
+	for i, k := range keys {
+		items[i] = Item{Key: k, TtlBox: s.values[k]}
+	}
+	s.mu.RUnlock()
+	return items
+}
+
+// keyHeap is a max-heap of keys, its root is the greatest key.
+type keyHeap []string
+
+func (h keyHeap) Len() int           { return len(h) }
+func (h keyHeap) Less(i, j int) bool { return h[i] > h[j] }
+func (h keyHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *keyHeap) Push(x interface{}) {
+	*h = append(*h, x.(string))
+}
+
+func (h *keyHeap) Pop() interface{} {
+	old := *h
+	k := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return k
+}
+
+func matchKey(key string, opts ScanOptions) bool {
+	if !strings.HasPrefix(key, opts.Prefix) {
+		return false
+	}
+	return opts.Match == "" || matchGlob(opts.Match, key)
+}
+
+// matchGlob reports whether the whole string matches the pattern with '*' and '?' wildcards.
+func matchGlob(pt, str string) bool {
+	pattern, s := []rune(pt), []rune(str)
+	p, i := 0, 0
+	star, mark := -1, 0
+	for i < len(s) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == s[i]):
+			p++
+			i++
+		case p < len(pattern) && pattern[p] == '*':
+			star, mark = p, i
+			p++
+		case star >= 0:
+			p = star + 1
+			mark++
+			i = mark
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
@@ -0,0 +1,152 @@
+package kv
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// Walks the cache by pages of 3 and checks that every key is returned once in order.
+func TestScanPages(t *testing.T) {
+	forEachShardCount(t, func(t *testing.T, c Cache, clock *FakeClock) {
+		var expected []string
+		for i := 0; i < 10; i++ {
+			key := fmt.Sprintf("user:%02d", i)
+			c.Add(key, T{V: []byte(key)})
+			expected = append(expected, key)
+		}
+		c.Add("order:1", T{V: []byte("order")})
+		c.AddWithTtl("user:expired", T{V: []byte("expired")}, time.Second)
+		clock.Advance(2 * time.Second)
+
+		var keys []string
+		cursor, pages := "", 0
+		for {
+			items, next := c.Scan(ScanOptions{Cursor: cursor, Count: 3, Prefix: "user:"})
+			for _, item := range items {
+				keys = append(keys, item.Key)
+				if string(item.Content.V) != item.Key || item.CreatedAt.IsZero() {
+					t.Errorf("unexpected item: %v", item)
+				}
+			}
+			pages++
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+		if !reflect.DeepEqual(keys, expected) {
+			t.Errorf("unexpected keys: %v", keys)
+		}
+		if pages != 4 {
+			t.Errorf("expected 4 pages, got %d", pages)
+		}
+
+		items, next := c.Scan(ScanOptions{Match: "*:0?"})
+		if len(items) != 10 || next != "" {
+			t.Errorf("expected 10 matched items in a single page, got %d %q", len(items), next)
+		}
+	})
+}
+
+// Compares the pages of many keys in a few shards with the sorted keys.
+func TestScanLargeShards(t *testing.T) {
+	c := NewCache(Configuration{ShardCount: 2})
+	rnd := rand.New(rand.NewSource(1))
+	expected := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("%08d", rnd.Intn(100000000))
+		c.Add(key, T{V: []byte(key)})
+		expected = append(expected, key)
+	}
+	sort.Strings(expected)
+	expected = dedup(expected)
+
+	var keys []string
+	cursor := ""
+	for {
+		items, next := c.Scan(ScanOptions{Cursor: cursor, Count: 7})
+		for _, item := range items {
+			keys = append(keys, item.Key)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected %d keys in order, got %d", len(expected), len(keys))
+	}
+}
+
+func dedup(keys []string) []string {
+	out := keys[:0]
+	for i, k := range keys {
+		if i == 0 || k != keys[i-1] {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// Empty keys cannot be written, so scans starting after the empty cursor return every key.
+func TestEmptyKey(t *testing.T) {
+	c := NewCache(Configuration{})
+	v := T{V: []byte("v")}
+	if err := c.Add("", v); err != ErrInvalidKey {
+		t.Errorf("expected ErrInvalidKey from Add, got %v", err)
+	}
+	if _, err := c.Set("", v, 0, WriteAlways); err != ErrInvalidKey {
+		t.Errorf("expected ErrInvalidKey from Set, got %v", err)
+	}
+	c.Add("a", v)
+	if _, err := c.CompareAndSwap("", 0, v, 0); err != ErrInvalidKey {
+		t.Errorf("expected ErrInvalidKey from CompareAndSwap, got %v", err)
+	}
+	if _, err := c.Incr("", 1, 0, false); err != ErrInvalidKey {
+		t.Errorf("expected ErrInvalidKey from Incr, got %v", err)
+	}
+	if _, err := c.Lock("", "owner", time.Second); err != ErrInvalidKey {
+		t.Errorf("expected ErrInvalidKey from Lock, got %v", err)
+	}
+	err := c.Update(func(tx Txn) error {
+		tx.Set("b", v, 0)
+		tx.Set("", v, 0)
+		return nil
+	})
+	if err != ErrInvalidKey {
+		t.Errorf("expected ErrInvalidKey from Update, got %v", err)
+	}
+	if _, ok := c.Value("b"); ok {
+		t.Error("expected the transaction not to be applied")
+	}
+	if items, next := c.Scan(ScanOptions{}); len(items) != 1 || items[0].Key != "a" || next != "" {
+		t.Errorf("expected only a, got %v %q", items, next)
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		pattern, s string
+		match      bool
+	}{
+		{"", "", true},
+		{"*", "a/b/c", true},
+		{"user:*", "user:1", true},
+		{"user:*", "order:1", false},
+		{"*:1", "user:1", true},
+		{"u?er", "user", true},
+		{"u?er", "usser", false},
+		{"a*b*c", "axxbyyc", true},
+		{"a*b*c", "axxbyy", false},
+		{"ключ?", "ключи", true},
+	}
+	for _, tc := range cases {
+		if got := matchGlob(tc.pattern, tc.s); got != tc.match {
+			t.Errorf("matchGlob(%q, %q) = %v", tc.pattern, tc.s, got)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package kv
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+const DefaultShardCount = 32
+
+// shard is an independently locked part of the cache. Keys are distributed
+// between shards by hash, so operations on different shards don't contend.
+type shard struct {
+	mu     sync.RWMutex
+	values map[string]TtlBox
+	expiry *expiryIndex
+	// dirty holds keys changed since the last backup, used with IncrementalStorage only.
+	dirty map[string]struct{}
+	// bytes is the estimated memory taken by the entries of the shard.
+	bytes int64
+
+	// policy is set only if the size of the shard is limited. Reads update it
+	// holding the read lock, so the calls are serialized by policyMu.
+	policyMu   sync.Mutex
+	policy     EvictionPolicy
+	maxEntries int
+	maxBytes   int64
+}
+
+func newShard(trackDirty bool) *shard {
+	s := &shard{
+		values: make(map[string]TtlBox),
+		expiry: newExpiryIndex(),
+	}
+	if trackDirty {
+		s.dirty = make(map[string]struct{})
+	}
+	return s
+}
+
+// put stores the box and updates the expiry index.
+// Must be called with the write lock held.
+func (s *shard) put(key string, box TtlBox) {
+	if old, ok := s.values[key]; ok {
+		s.bytes -= entrySize(key, old)
+	}
+	s.values[key] = box
+	s.bytes += entrySize(key, box)
+	s.expiry.set(key, box.Expired)
+	if s.policy != nil {
+		s.policyMu.Lock()
+		s.policy.Add(key, box)
+		s.policyMu.Unlock()
+	}
+}
+
+// delete removes the key from the shard and the expiry index.
+// Must be called with the write lock held.
+func (s *shard) delete(key string) {
+	if old, ok := s.values[key]; ok {
+		s.bytes -= entrySize(key, old)
+	}
+	delete(s.values, key)
+	s.expiry.remove(key)
+	if s.policy != nil {
+		s.policyMu.Lock()
+		s.policy.Remove(key)
+		s.policyMu.Unlock()
+	}
+}
+
+// touch updates the eviction metadata of the key on read.
+// Must be called with the read or write lock held.
+func (s *shard) touch(key string) {
+	if s.policy != nil {
+		s.policyMu.Lock()
+		s.policy.Access(key)
+		s.policyMu.Unlock()
+	}
+}
+
+// overLimit reports whether the given number of entries and their size exceed the shard limits.
+func (s *shard) overLimit(entries int, bytes int64) bool {
+	return s.maxEntries > 0 && entries > s.maxEntries || s.maxBytes > 0 && bytes > s.maxBytes
+}
+
+// markDirty remembers the key for the next incremental backup.
+// Must be called with the write lock held.
+func (s *shard) markDirty(key string) {
+	if s.dirty != nil {
+		s.dirty[key] = struct{}{}
+	}
+}
+
+// shardFor returns the shard responsible for the key.
+func (c *cache) shardFor(key string) *shard {
+	return c.shards[c.shardIndex(key)]
+}
+
+// shardIndex returns the index of the shard responsible for the key.
+func (c *cache) shardIndex(key string) int {
+	if len(c.shards) == 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(len(c.shards)))
+}
@@ -0,0 +1,19 @@
+package kv
+
+import "sync/atomic"
+
+// Stats holds the counters of the cache since it was created.
+type Stats struct {
+	// Evictions is the number of keys deleted to keep the cache within its size limits.
+	Evictions uint64
+	// Expirations is the number of keys deleted because of their TTL.
+	Expirations uint64
+}
+
+// Stats returns the current values of the cache counters.
+func (c *cache) Stats() Stats {
+	return Stats{
+		Evictions:   atomic.LoadUint64(&c.evictions),
+		Expirations: atomic.LoadUint64(&c.expirations),
+	}
+}
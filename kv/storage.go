@@ -7,6 +7,67 @@ type Storage interface {
 	Save(map[string]TtlBox) error
 }
 
+// IncrementalStorage is an optional extension of Storage for backends that can apply
+// only the changes made since the previous backup instead of rewriting all the data.
+// SaveChanges receives the current state of the keys that were added or modified
+// and the list of keys that were removed. If it fails, the same keys are passed again
+// on the next backup.
+type IncrementalStorage interface {
+	Storage
+	SaveChanges(updated map[string]TtlBox, deleted []string) error
+}
+
+// LogStorage is an optional extension of Storage for backends that record every
+// change of the cache between snapshots. Append is called before a cache method
+// returns, the changes passed to a single call must be restored all together or not at all.
+// Sync is called according to the configured SyncMode. Rotate is called
+// right before the snapshot data is taken, so the changes appended before the call
+// are covered by the next Save and can be discarded after it succeeds.
+type LogStorage interface {
+	Storage
+	Append(changes ...Change) error
+	Sync() error
+	Rotate() error
+}
+
+// versionKey is the key under which the storage data holds the greatest version given by the cache,
+// so the versions keep growing after a restart even if the entries that had them are deleted.
+// Snapshots contain it, the log has it in every batch of changes that deletes a key.
+// It is the empty key, which cannot be written.
+const versionKey = ""
+
+// ChangeKind specifies the type of a modification made to the cache.
+type ChangeKind int
+
+const (
+	// ChangeSet means that a value was stored or its TTL was changed.
+	ChangeSet ChangeKind = iota
+	// ChangeDelete means that a key was removed from the cache.
+	ChangeDelete
+)
+
+// Change describes a single modification of the cache. Box holds the new state
+// of the entry and is empty for deletions.
+type Change struct {
+	Kind ChangeKind
+	Key  string
+	Box  TtlBox
+	// Event is the kind of the event published for the change when it is not
+	// the plain EventSet or EventDelete, e.g. EventExpire for a deletion by the cleaner.
+	Event EventKind
+}
+
+// event returns the kind of the event published for the change.
+func (ch Change) event() EventKind {
+	if ch.Event != EventSet {
+		return ch.Event
+	}
+	if ch.Kind == ChangeDelete {
+		return EventDelete
+	}
+	return EventSet
+}
+
 type UnimplementedStorage struct{}
 
 func (s *UnimplementedStorage) RestoreInto(*map[string]TtlBox) error {
@@ -0,0 +1,174 @@
+package kv
+
+import (
+	"sort"
+	"time"
+)
+
+// maxTxnAttempts limits the number of times Update runs a transaction conflicting with other writes.
+const maxTxnAttempts = 10
+
+// Txn groups reads and writes applied to the cache all together or not at all.
+// Writes are buffered until the transaction commits, reads see them.
+type Txn interface {
+	// Get returns the entry for the key, see Cache.Entry.
+	Get(key string) (TtlBox, bool)
+	// Set stores the value for the key, see Cache.Set with WriteAlways.
+	Set(key string, value T, ttl time.Duration)
+	// Delete removes the key.
+	Delete(key string)
+}
+
+// txnWrite is a buffered write of a transaction, nil value means deletion.
+type txnWrite struct {
+	value *T
+	ttl   time.Duration
+}
+
+type txn struct {
+	c *cache
+	// reads holds the versions of the keys read from the cache, zero for absent ones.
+	reads  map[string]uint64
+	writes map[string]txnWrite
+	// order holds the written keys in the order of the first write.
+	order []string
+}
+
+func (tx *txn) Get(key string) (TtlBox, bool) {
+	if w, ok := tx.writes[key]; ok {
+		if w.value == nil {
+			return TtlBox{}, false
+		}
+		now := tx.c.clock.Now()
+		return TtlBox{CreatedAt: now, Expired: tx.c.expiration(now, w.ttl), Content: *w.value}, true
+	}
+	box, ok := tx.c.lookup(key)
+	if _, seen := tx.reads[key]; !seen {
+		tx.reads[key] = box.Version
+	}
+	return box, ok
+}
+
+func (tx *txn) Set(key string, value T, ttl time.Duration) {
+	tx.write(key, txnWrite{value: &value, ttl: ttl})
+}
+
+func (tx *txn) Delete(key string) {
+	tx.write(key, txnWrite{})
+}
+
+func (tx *txn) write(key string, w txnWrite) {
+	if _, ok := tx.writes[key]; !ok {
+		tx.order = append(tx.order, key)
+	}
+	tx.writes[key] = w
+}
+
+// Update runs fn in a transaction and commits its writes if fn returns nil.
+// The transaction commits only if the keys read by fn haven't changed since,
+// otherwise fn is run again, so it must not have other side effects.
+// ErrConflict is returned if the transaction keeps conflicting with other writes.
+// All the changes of a transaction are logged as a single batch and snapshots
+// contain either all of them or none.
+func (c *cache) Update(fn func(tx Txn) error) error {
+	for i := 0; i < maxTxnAttempts; i++ {
+		tx := &txn{c: c, reads: make(map[string]uint64), writes: make(map[string]txnWrite)}
+		if err := fn(tx); err != nil {
+			return err
+		}
+		err := c.commit(tx)
+		if err != ErrConflict {
+			return err
+		}
+	}
+	return ErrConflict
+}
+
+// commit locks the shards of the transaction keys in ascending order,
+// validates the reads and applies the writes.
+func (c *cache) commit(tx *txn) error {
+	if len(tx.writes) == 0 {
+		return nil
+	}
+	c.txMu.RLock()
+	defer c.txMu.RUnlock()
+	keys := make([]string, 0, len(tx.reads)+len(tx.writes))
+	for k := range tx.reads {
+		keys = append(keys, k)
+	}
+	for k := range tx.writes {
+		keys = append(keys, k)
+	}
+	defer c.lockShards(keys)()
+
+	now := c.clock.Now()
+	for k, version := range tx.reads {
+		box, ok := c.shardFor(k).values[k]
+		if !ok || box.IsExpired(now) {
+			box = TtlBox{}
+		}
+		if box.Version != version {
+			return ErrConflict
+		}
+	}
+	changes := make([]Change, 0, len(tx.order))
+	for _, k := range tx.order {
+		w := tx.writes[k]
+		if w.value == nil {
+			if _, ok := c.shardFor(k).values[k]; ok {
+				changes = append(changes, Change{Kind: ChangeDelete, Key: k})
+			}
+			continue
+		}
+		box := TtlBox{
+			CreatedAt: now,
+			Expired:   c.expiration(now, w.ttl),
+			Content:   *w.value,
+			Version:   c.nextVersion(),
+		}
+		changes = append(changes, Change{Kind: ChangeSet, Key: k, Box: box})
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	if err := c.journal(changes...); err != nil {
+		return err
+	}
+	for _, ch := range changes {
+		s := c.shardFor(ch.Key)
+		if ch.Kind == ChangeDelete {
+			old := s.values[ch.Key]
+			s.delete(ch.Key)
+			s.markDirty(ch.Key)
+			c.events.publish(Event{Kind: EventDelete, Key: ch.Key, TtlBox: old})
+			continue
+		}
+		c.makeRoom(s, ch.Key, ch.Box, now)
+		s.put(ch.Key, ch.Box)
+		s.markDirty(ch.Key)
+		c.events.publish(Event{Kind: EventSet, Key: ch.Key, TtlBox: ch.Box})
+	}
+	return nil
+}
+
+// lockShards locks the shards of the keys for writing in ascending order,
+// so callers locking several shards don't deadlock. Returns the function unlocking them.
+func (c *cache) lockShards(keys []string) func() {
+	locked := make(map[int]bool)
+	for _, k := range keys {
+		locked[c.shardIndex(k)] = true
+	}
+	indexes := make([]int, 0, len(locked))
+	for i := range locked {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	for _, i := range indexes {
+		c.shards[i].mu.Lock()
+	}
+	return func() {
+		for _, i := range indexes {
+			c.shards[i].mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestUpdate(t *testing.T) {
+	forEachShardCount(t, func(t *testing.T, c Cache, clock *FakeClock) {
+		c.Add("user:1", T{V: []byte("john")})
+		c.Add("name:john", T{V: []byte("1")})
+		sub := c.Subscribe(SubscribeOptions{})
+		defer sub.Close()
+		err := c.Update(func(tx Txn) error {
+			box, ok := tx.Get("user:1")
+			if !ok {
+				t.Fatal("expected user to be read")
+			}
+			tx.Delete("name:" + string(box.Content.V))
+			tx.Set("user:1", T{V: []byte("jack")}, 0)
+			tx.Set("name:jack", T{V: []byte("1")}, 0)
+			if v, _ := tx.Get("user:1"); string(v.Content.V) != "jack" {
+				t.Errorf("expected transaction to read its writes, got %s", v.Content.V)
+			}
+			if _, ok := tx.Get("name:john"); ok {
+				t.Error("expected deleted key to be absent in transaction")
+			}
+			if v, _ := c.Value("user:1"); string(v.V) != "john" {
+				t.Errorf("expected writes to be invisible before commit, got %s", v.V)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := c.Value("name:john"); ok {
+			t.Error("expected old index key to be deleted")
+		}
+		if v, _ := c.Value("name:jack"); string(v.V) != "1" {
+			t.Errorf("expected new index key, got %s", v.V)
+		}
+		if len(sub.C) != 3 {
+			t.Errorf("expected an event for every change, got %d", len(sub.C))
+		}
+
+		failure := errors.New("failure")
+		err = c.Update(func(tx Txn) error {
+			tx.Set("user:1", T{V: []byte("bill")}, 0)
+			return failure
+		})
+		if err != failure {
+			t.Errorf("expected the error of the function, got %v", err)
+		}
+		if v, _ := c.Value("user:1"); string(v.V) != "jack" {
+			t.Errorf("expected nothing to be applied, got %s", v.V)
+		}
+	})
+}
+
+func TestUpdateConflict(t *testing.T) {
+	c := NewCache(Configuration{})
+	defer c.Close(context.Background())
+	c.Add("counter", T{V: []byte("1")})
+
+	attempts := 0
+	err := c.Update(func(tx Txn) error {
+		attempts++
+		tx.Get("counter")
+		if attempts == 1 {
+			c.Add("counter", T{V: []byte("2")})
+		}
+		tx.Set("copy", T{V: []byte("v")}, 0)
+		return nil
+	})
+	if err != nil || attempts != 2 {
+		t.Errorf("expected the transaction to be retried once, got %d attempts %v", attempts, err)
+	}
+
+	attempts = 0
+	err = c.Update(func(tx Txn) error {
+		attempts++
+		tx.Get("counter")
+		c.Add("counter", T{V: []byte("3")})
+		tx.Set("copy", T{V: []byte("v")}, 0)
+		return nil
+	})
+	if err != ErrConflict || attempts != maxTxnAttempts {
+		t.Errorf("expected conflict after %d attempts, got %d %v", maxTxnAttempts, attempts, err)
+	}
+}
@@ -0,0 +1,31 @@
+package kv
+
+import "encoding/json"
+
+// jsonT is the json representation of T used by storages. The payload is kept
+// base64 encoded in B. Snapshots made before values became binary hold plain
+// strings in V, they are still accepted when decoding.
+type jsonT struct {
+	B           []byte  `json:",omitempty"`
+	V           *string `json:",omitempty"`
+	ContentType string  `json:",omitempty"`
+}
+
+// MarshalJSON makes the T type implement the json.Marshaler interface.
+func (t T) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonT{B: t.V, ContentType: t.ContentType})
+}
+
+// UnmarshalJSON makes the T type implement the json.Unmarshaler interface.
+func (t *T) UnmarshalJSON(data []byte) error {
+	var j jsonT
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	t.V = j.B
+	if j.B == nil && j.V != nil {
+		t.V = []byte(*j.V)
+	}
+	t.ContentType = j.ContentType
+	return nil
+}
@@ -1,40 +1,129 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	_ "github.com/lib/pq"
 	"google.golang.org/grpc"
 	"kv-ttl/kv"
 	"kv-ttl/pb"
+	"kv-ttl/raft"
 	"kv-ttl/repository"
 	"kv-ttl/repository/postgres"
 	"kv-ttl/server"
 	"log"
 	"net"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
 	"time"
 )
 
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	log.SetFlags(log.Flags() | log.Lshortfile)
 
 	cacheConfig := kv.Configuration{
 		BackupInterval: backupInterval(),
 		Storage:        storage(),
-	}
-	cache := kv.NewCache(cacheConfig)
-	cacheServer := server.NewCacheServer(cache)
+		SyncMode:       syncMode(),
+		ShardCount:     shardCount(),
+		CleanInterval:  cleanInterval(),
 
+		MaxEntries:        maxEntries(),
+		MaxBytes:          maxMemory(),
+		NewEvictionPolicy: evictionPolicy(),
+	}
 	opts := make([]grpc.ServerOption, 0)
 	grpcServer := grpc.NewServer(opts...)
+
+	var node *raft.Node
+	var cache kv.Cache
+	if id := os.Getenv("RAFT_ID"); id != "" {
+		transport := raft.NewGrpcTransport(grpc.WithInsecure())
+		defer transport.Close()
+		var err error
+		node, err = raft.NewNode(raft.Config{
+			ID:        id,
+			Address:   os.Getenv("RAFT_ADDRESS"),
+			Storage:   cacheConfig.Storage,
+			Transport: transport,
+			Bootstrap: os.Getenv("RAFT_BOOTSTRAP") == "true",
+			Cache:     cacheConfig,
+		})
+		if err == raft.ErrNotLogStorage {
+			log.Fatal("cluster members need STORAGE=\"wal\" or no storage")
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		cache = node.Cache()
+		// The storage belongs to the node, the namespaces are not replicated.
+		cacheConfig.Storage = nil
+		pb.RegisterClusterServer(grpcServer, server.NewClusterServer(node))
+		fmt.Printf("started as the cluster member %s\n", id)
+	} else {
+		// Followers change only with the leader, see kv.Configuration.ReadOnly.
+		cacheConfig.ReadOnly = os.Getenv("LEADER") != ""
+		cache = kv.NewCache(cacheConfig)
+	}
+	namespaces := kv.NewNamespaces(cache, cacheConfig)
+	cacheServer := server.NewNamespacedServer(namespaces)
+	if node != nil {
+		cacheServer = server.NewReplicaServer(namespaces, node)
+	}
+
+	replication, stopReplication := context.WithCancel(context.Background())
+	replicationDone := make(chan struct{})
+	if leader := os.Getenv("LEADER"); leader != "" && node == nil {
+		conn, err := grpc.Dial(leader, grpc.WithInsecure())
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer conn.Close()
+		follower := server.NewFollower(namespaces, pb.NewStorageClient(conn), server.FollowerOptions{Leader: leader})
+		go func() {
+			defer close(replicationDone)
+			follower.Run(replication)
+		}()
+		cacheServer = server.NewReplicaServer(namespaces, follower)
+		fmt.Printf("started as a follower of %s\n", leader)
+	} else {
+		close(replicationDone)
+	}
+
 	pb.RegisterStorageServer(grpcServer, cacheServer)
 
 	listener, err := net.Listen("tcp", ":80")
 	if err != nil {
 		log.Fatal(err)
 	}
-	grpcServer.Serve(listener)
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
+	fmt.Println("shutting down")
+
+	grpcServer.GracefulStop()
+	stopReplication()
+	<-replicationDone
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if node != nil {
+		if err := node.Close(ctx); err != nil {
+			log.Println(err)
+		}
+	}
+	if err := namespaces.Close(ctx); err != nil {
+		log.Println(err)
+	}
 }
 
 func backupInterval() time.Duration {
@@ -49,6 +138,56 @@ func backupInterval() time.Duration {
 	return time.Duration(bi)
 }
 
+// cleanInterval parses the period of the expired values cleaner in milliseconds.
+func cleanInterval() time.Duration {
+	ci, err := strconv.Atoi(os.Getenv("CLEAN_INTERVAL"))
+	if err != nil {
+		return kv.DefaultCleanInterval
+	}
+	return time.Duration(ci) * time.Millisecond
+}
+
+// shardCount parses the number of cache shards, zero means the default one.
+func shardCount() int {
+	n, err := strconv.Atoi(os.Getenv("SHARDS"))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// maxEntries parses the limit of the number of keys, zero means no limit.
+func maxEntries() int {
+	n, err := strconv.Atoi(os.Getenv("MAX_ENTRIES"))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// maxMemory parses the limit of the cache data size in bytes, zero means no limit.
+func maxMemory() int64 {
+	n, err := strconv.ParseInt(os.Getenv("MAX_MEMORY"), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// evictionPolicy parses the policy used when the cache size is limited.
+func evictionPolicy() func() kv.EvictionPolicy {
+	switch os.Getenv("EVICTION") {
+	case "lfu":
+		return kv.NewLfuPolicy
+	case "random":
+		return kv.NewRandomPolicy
+	case "ttl":
+		return kv.NewTtlPolicy
+	default:
+		return kv.NewLruPolicy
+	}
+}
+
 // storage parses environment variables and configures one of supported data storages.
 func storage() kv.Storage {
 	switch os.Getenv("STORAGE") {
@@ -76,8 +215,25 @@ func storage() kv.Storage {
 		fmt.Printf("started with file storage => %s\n", filename)
 		return repository.NewFileRepo(filename)
 
+	case "wal":
+		filename := os.Getenv("FNAME")
+		fmt.Printf("started with write-ahead log storage => %s\n", filename)
+		return repository.NewWalRepo(filename)
+
 	default:
 		fmt.Println("started without persistent storage")
 		return &kv.UnimplementedStorage{}
 	}
 }
+
+// syncMode parses the flush policy for storages that log every change.
+func syncMode() kv.SyncMode {
+	switch os.Getenv("WAL_SYNC") {
+	case "periodic":
+		return kv.SyncPeriodic
+	case "never":
+		return kv.SyncNever
+	default:
+		return kv.SyncAlways
+	}
+}
@@ -26,6 +26,219 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type WriteMode int32
+
+const (
+	WriteMode_ALWAYS     WriteMode = 0
+	WriteMode_IF_ABSENT  WriteMode = 1
+	WriteMode_IF_PRESENT WriteMode = 2
+)
+
+var WriteMode_name = map[int32]string{
+	0: "ALWAYS",
+	1: "IF_ABSENT",
+	2: "IF_PRESENT",
+}
+
+var WriteMode_value = map[string]int32{
+	"ALWAYS":     0,
+	"IF_ABSENT":  1,
+	"IF_PRESENT": 2,
+}
+
+func (x WriteMode) String() string {
+	return proto.EnumName(WriteMode_name, int32(x))
+}
+
+func (WriteMode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{0}
+}
+
+type EventType int32
+
+const (
+	EventType_SET    EventType = 0
+	EventType_DELETE EventType = 1
+	EventType_EXPIRE EventType = 2
+	EventType_TTL    EventType = 3
+	EventType_EVICT  EventType = 4
+	// SLIDE is sent only to replication followers.
+	EventType_SLIDE EventType = 5
+)
+
+var EventType_name = map[int32]string{
+	0: "SET",
+	1: "DELETE",
+	2: "EXPIRE",
+	3: "TTL",
+	4: "EVICT",
+	5: "SLIDE",
+}
+
+var EventType_value = map[string]int32{
+	"SET":    0,
+	"DELETE": 1,
+	"EXPIRE": 2,
+	"TTL":    3,
+	"EVICT":  4,
+	"SLIDE":  5,
+}
+
+func (x EventType) String() string {
+	return proto.EnumName(EventType_name, int32(x))
+}
+
+func (EventType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{1}
+}
+
+type TxnCheck_Kind int32
+
+const (
+	// VERSION requires the key to have the version, zero means that the key is absent.
+	TxnCheck_VERSION TxnCheck_Kind = 0
+	TxnCheck_EXISTS  TxnCheck_Kind = 1
+	TxnCheck_ABSENT  TxnCheck_Kind = 2
+)
+
+var TxnCheck_Kind_name = map[int32]string{
+	0: "VERSION",
+	1: "EXISTS",
+	2: "ABSENT",
+}
+
+var TxnCheck_Kind_value = map[string]int32{
+	"VERSION": 0,
+	"EXISTS":  1,
+	"ABSENT":  2,
+}
+
+func (x TxnCheck_Kind) String() string {
+	return proto.EnumName(TxnCheck_Kind_name, int32(x))
+}
+
+func (TxnCheck_Kind) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{30, 0}
+}
+
+type TxnOp_Kind int32
+
+const (
+	TxnOp_SET    TxnOp_Kind = 0
+	TxnOp_DELETE TxnOp_Kind = 1
+)
+
+var TxnOp_Kind_name = map[int32]string{
+	0: "SET",
+	1: "DELETE",
+}
+
+var TxnOp_Kind_value = map[string]int32{
+	"SET":    0,
+	"DELETE": 1,
+}
+
+func (x TxnOp_Kind) String() string {
+	return proto.EnumName(TxnOp_Kind_name, int32(x))
+}
+
+func (TxnOp_Kind) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{31, 0}
+}
+
+type ReplicationMessage_Kind int32
+
+const (
+	ReplicationMessage_ENTRY            ReplicationMessage_Kind = 0
+	ReplicationMessage_SNAPSHOT_END     ReplicationMessage_Kind = 1
+	ReplicationMessage_EVENT            ReplicationMessage_Kind = 2
+	ReplicationMessage_HEARTBEAT        ReplicationMessage_Kind = 3
+	ReplicationMessage_NAMESPACE_CREATE ReplicationMessage_Kind = 4
+	ReplicationMessage_NAMESPACE_DROP   ReplicationMessage_Kind = 5
+	ReplicationMessage_SYNCED           ReplicationMessage_Kind = 6
+)
+
+var ReplicationMessage_Kind_name = map[int32]string{
+	0: "ENTRY",
+	1: "SNAPSHOT_END",
+	2: "EVENT",
+	3: "HEARTBEAT",
+	4: "NAMESPACE_CREATE",
+	5: "NAMESPACE_DROP",
+	6: "SYNCED",
+}
+
+var ReplicationMessage_Kind_value = map[string]int32{
+	"ENTRY":            0,
+	"SNAPSHOT_END":     1,
+	"EVENT":            2,
+	"HEARTBEAT":        3,
+	"NAMESPACE_CREATE": 4,
+	"NAMESPACE_DROP":   5,
+	"SYNCED":           6,
+}
+
+func (x ReplicationMessage_Kind) String() string {
+	return proto.EnumName(ReplicationMessage_Kind_name, int32(x))
+}
+
+func (ReplicationMessage_Kind) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{39, 0}
+}
+
+type ReplicationInfo_Role int32
+
+const (
+	ReplicationInfo_LEADER   ReplicationInfo_Role = 0
+	ReplicationInfo_FOLLOWER ReplicationInfo_Role = 1
+)
+
+var ReplicationInfo_Role_name = map[int32]string{
+	0: "LEADER",
+	1: "FOLLOWER",
+}
+
+var ReplicationInfo_Role_value = map[string]int32{
+	"LEADER":   0,
+	"FOLLOWER": 1,
+}
+
+func (x ReplicationInfo_Role) String() string {
+	return proto.EnumName(ReplicationInfo_Role_name, int32(x))
+}
+
+func (ReplicationInfo_Role) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{40, 0}
+}
+
+type RaftEntry_Kind int32
+
+const (
+	RaftEntry_NOOP    RaftEntry_Kind = 0
+	RaftEntry_CHANGES RaftEntry_Kind = 1
+	RaftEntry_MEMBERS RaftEntry_Kind = 2
+)
+
+var RaftEntry_Kind_name = map[int32]string{
+	0: "NOOP",
+	1: "CHANGES",
+	2: "MEMBERS",
+}
+
+var RaftEntry_Kind_value = map[string]int32{
+	"NOOP":    0,
+	"CHANGES": 1,
+	"MEMBERS": 2,
+}
+
+func (x RaftEntry_Kind) String() string {
+	return proto.EnumName(RaftEntry_Kind_name, int32(x))
+}
+
+func (RaftEntry_Kind) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{43, 0}
+}
+
 type Empty struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -57,8 +270,201 @@ func (m *Empty) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Empty proto.InternalMessageInfo
 
+// Every request has a namespace, the empty one stands for the "default" namespace.
+type Namespace struct {
+	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Namespace) Reset()         { *m = Namespace{} }
+func (m *Namespace) String() string { return proto.CompactTextString(m) }
+func (*Namespace) ProtoMessage()    {}
+func (*Namespace) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{1}
+}
+
+func (m *Namespace) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Namespace.Unmarshal(m, b)
+}
+func (m *Namespace) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Namespace.Marshal(b, m, deterministic)
+}
+func (m *Namespace) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Namespace.Merge(m, src)
+}
+func (m *Namespace) XXX_Size() int {
+	return xxx_messageInfo_Namespace.Size(m)
+}
+func (m *Namespace) XXX_DiscardUnknown() {
+	xxx_messageInfo_Namespace.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Namespace proto.InternalMessageInfo
+
+func (m *Namespace) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type NamespaceInfo struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// default_ttl is applied to the values stored without ttl.
+	DefaultTtl           *duration.Duration `protobuf:"bytes,2,opt,name=default_ttl,json=defaultTtl,proto3" json:"default_ttl,omitempty"`
+	MaxEntries           int64              `protobuf:"varint,3,opt,name=max_entries,json=maxEntries,proto3" json:"max_entries,omitempty"`
+	MaxBytes             int64              `protobuf:"varint,4,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *NamespaceInfo) Reset()         { *m = NamespaceInfo{} }
+func (m *NamespaceInfo) String() string { return proto.CompactTextString(m) }
+func (*NamespaceInfo) ProtoMessage()    {}
+func (*NamespaceInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{2}
+}
+
+func (m *NamespaceInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NamespaceInfo.Unmarshal(m, b)
+}
+func (m *NamespaceInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NamespaceInfo.Marshal(b, m, deterministic)
+}
+func (m *NamespaceInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NamespaceInfo.Merge(m, src)
+}
+func (m *NamespaceInfo) XXX_Size() int {
+	return xxx_messageInfo_NamespaceInfo.Size(m)
+}
+func (m *NamespaceInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_NamespaceInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NamespaceInfo proto.InternalMessageInfo
+
+func (m *NamespaceInfo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *NamespaceInfo) GetDefaultTtl() *duration.Duration {
+	if m != nil {
+		return m.DefaultTtl
+	}
+	return nil
+}
+
+func (m *NamespaceInfo) GetMaxEntries() int64 {
+	if m != nil {
+		return m.MaxEntries
+	}
+	return 0
+}
+
+func (m *NamespaceInfo) GetMaxBytes() int64 {
+	if m != nil {
+		return m.MaxBytes
+	}
+	return 0
+}
+
+type NamespaceList struct {
+	Namespaces           []*NamespaceInfo `protobuf:"bytes,1,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *NamespaceList) Reset()         { *m = NamespaceList{} }
+func (m *NamespaceList) String() string { return proto.CompactTextString(m) }
+func (*NamespaceList) ProtoMessage()    {}
+func (*NamespaceList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{3}
+}
+
+func (m *NamespaceList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NamespaceList.Unmarshal(m, b)
+}
+func (m *NamespaceList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NamespaceList.Marshal(b, m, deterministic)
+}
+func (m *NamespaceList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NamespaceList.Merge(m, src)
+}
+func (m *NamespaceList) XXX_Size() int {
+	return xxx_messageInfo_NamespaceList.Size(m)
+}
+func (m *NamespaceList) XXX_DiscardUnknown() {
+	xxx_messageInfo_NamespaceList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NamespaceList proto.InternalMessageInfo
+
+func (m *NamespaceList) GetNamespaces() []*NamespaceInfo {
+	if m != nil {
+		return m.Namespaces
+	}
+	return nil
+}
+
+type StatsResponse struct {
+	// evictions is the number of keys deleted to keep the namespace within its limits.
+	Evictions uint64 `protobuf:"varint,1,opt,name=evictions,proto3" json:"evictions,omitempty"`
+	// expirations is the number of keys deleted because of their ttl.
+	Expirations          uint64   `protobuf:"varint,2,opt,name=expirations,proto3" json:"expirations,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatsResponse) Reset()         { *m = StatsResponse{} }
+func (m *StatsResponse) String() string { return proto.CompactTextString(m) }
+func (*StatsResponse) ProtoMessage()    {}
+func (*StatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{4}
+}
+
+func (m *StatsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatsResponse.Unmarshal(m, b)
+}
+func (m *StatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatsResponse.Marshal(b, m, deterministic)
+}
+func (m *StatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatsResponse.Merge(m, src)
+}
+func (m *StatsResponse) XXX_Size() int {
+	return xxx_messageInfo_StatsResponse.Size(m)
+}
+func (m *StatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatsResponse proto.InternalMessageInfo
+
+func (m *StatsResponse) GetEvictions() uint64 {
+	if m != nil {
+		return m.Evictions
+	}
+	return 0
+}
+
+func (m *StatsResponse) GetExpirations() uint64 {
+	if m != nil {
+		return m.Expirations
+	}
+	return 0
+}
+
 type Key struct {
 	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Namespace            string   `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -68,7 +474,7 @@ func (m *Key) Reset()         { *m = Key{} }
 func (m *Key) String() string { return proto.CompactTextString(m) }
 func (*Key) ProtoMessage()    {}
 func (*Key) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5fca3b110c9bbf3a, []int{1}
+	return fileDescriptor_5fca3b110c9bbf3a, []int{5}
 }
 
 func (m *Key) XXX_Unmarshal(b []byte) error {
@@ -96,8 +502,17 @@ func (m *Key) GetKey() string {
 	return ""
 }
 
+func (m *Key) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
 type T struct {
-	Value                string   `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Value                []byte   `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Version              uint64   `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	ContentType          string   `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -107,7 +522,7 @@ func (m *T) Reset()         { *m = T{} }
 func (m *T) String() string { return proto.CompactTextString(m) }
 func (*T) ProtoMessage()    {}
 func (*T) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5fca3b110c9bbf3a, []int{2}
+	return fileDescriptor_5fca3b110c9bbf3a, []int{6}
 }
 
 func (m *T) XXX_Unmarshal(b []byte) error {
@@ -128,16 +543,31 @@ func (m *T) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_T proto.InternalMessageInfo
 
-func (m *T) GetValue() string {
+func (m *T) GetValue() []byte {
 	if m != nil {
 		return m.Value
 	}
+	return nil
+}
+
+func (m *T) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *T) GetContentType() string {
+	if m != nil {
+		return m.ContentType
+	}
 	return ""
 }
 
 type KeyValue struct {
 	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	Value                *T       `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Namespace            string   `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -147,7 +577,7 @@ func (m *KeyValue) Reset()         { *m = KeyValue{} }
 func (m *KeyValue) String() string { return proto.CompactTextString(m) }
 func (*KeyValue) ProtoMessage()    {}
 func (*KeyValue) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5fca3b110c9bbf3a, []int{3}
+	return fileDescriptor_5fca3b110c9bbf3a, []int{7}
 }
 
 func (m *KeyValue) XXX_Unmarshal(b []byte) error {
@@ -182,20 +612,30 @@ func (m *KeyValue) GetValue() *T {
 	return nil
 }
 
+func (m *KeyValue) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
 type KeyValueTtl struct {
-	Key                  string             `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	Value                *T                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
-	Ttl                  *duration.Duration `protobuf:"bytes,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
-	XXX_unrecognized     []byte             `json:"-"`
-	XXX_sizecache        int32              `json:"-"`
+	Key   string             `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value *T                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Ttl   *duration.Duration `protobuf:"bytes,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// sliding makes every read of the value renew the ttl.
+	Sliding              bool     `protobuf:"varint,4,opt,name=sliding,proto3" json:"sliding,omitempty"`
+	Namespace            string   `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *KeyValueTtl) Reset()         { *m = KeyValueTtl{} }
 func (m *KeyValueTtl) String() string { return proto.CompactTextString(m) }
 func (*KeyValueTtl) ProtoMessage()    {}
 func (*KeyValueTtl) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5fca3b110c9bbf3a, []int{4}
+	return fileDescriptor_5fca3b110c9bbf3a, []int{8}
 }
 
 func (m *KeyValueTtl) XXX_Unmarshal(b []byte) error {
@@ -237,9 +677,24 @@ func (m *KeyValueTtl) GetTtl() *duration.Duration {
 	return nil
 }
 
+func (m *KeyValueTtl) GetSliding() bool {
+	if m != nil {
+		return m.Sliding
+	}
+	return false
+}
+
+func (m *KeyValueTtl) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
 type TtlRequest struct {
 	Key                  string               `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	Stamp                *timestamp.Timestamp `protobuf:"bytes,2,opt,name=stamp,proto3" json:"stamp,omitempty"`
+	Namespace            string               `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
 	XXX_unrecognized     []byte               `json:"-"`
 	XXX_sizecache        int32                `json:"-"`
@@ -249,7 +704,7 @@ func (m *TtlRequest) Reset()         { *m = TtlRequest{} }
 func (m *TtlRequest) String() string { return proto.CompactTextString(m) }
 func (*TtlRequest) ProtoMessage()    {}
 func (*TtlRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5fca3b110c9bbf3a, []int{5}
+	return fileDescriptor_5fca3b110c9bbf3a, []int{9}
 }
 
 func (m *TtlRequest) XXX_Unmarshal(b []byte) error {
@@ -284,404 +739,3883 @@ func (m *TtlRequest) GetStamp() *timestamp.Timestamp {
 	return nil
 }
 
-type TtlResponse struct {
-	Ttl                  *duration.Duration `protobuf:"bytes,1,opt,name=ttl,proto3" json:"ttl,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
-	XXX_unrecognized     []byte             `json:"-"`
-	XXX_sizecache        int32              `json:"-"`
+func (m *TtlRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
 }
 
-func (m *TtlResponse) Reset()         { *m = TtlResponse{} }
-func (m *TtlResponse) String() string { return proto.CompactTextString(m) }
-func (*TtlResponse) ProtoMessage()    {}
-func (*TtlResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5fca3b110c9bbf3a, []int{6}
+type TtlInfo struct {
+	// remaining is the time left until the key expires, unset if no_expiry is true.
+	Remaining            *duration.Duration   `protobuf:"bytes,1,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	NoExpiry             bool                 `protobuf:"varint,2,opt,name=no_expiry,json=noExpiry,proto3" json:"no_expiry,omitempty"`
+	Expired              *timestamp.Timestamp `protobuf:"bytes,3,opt,name=expired,proto3" json:"expired,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
-func (m *TtlResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_TtlResponse.Unmarshal(m, b)
+func (m *TtlInfo) Reset()         { *m = TtlInfo{} }
+func (m *TtlInfo) String() string { return proto.CompactTextString(m) }
+func (*TtlInfo) ProtoMessage()    {}
+func (*TtlInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{10}
 }
-func (m *TtlResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_TtlResponse.Marshal(b, m, deterministic)
+
+func (m *TtlInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TtlInfo.Unmarshal(m, b)
 }
-func (m *TtlResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TtlResponse.Merge(m, src)
+func (m *TtlInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TtlInfo.Marshal(b, m, deterministic)
 }
-func (m *TtlResponse) XXX_Size() int {
-	return xxx_messageInfo_TtlResponse.Size(m)
+func (m *TtlInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TtlInfo.Merge(m, src)
 }
-func (m *TtlResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_TtlResponse.DiscardUnknown(m)
+func (m *TtlInfo) XXX_Size() int {
+	return xxx_messageInfo_TtlInfo.Size(m)
+}
+func (m *TtlInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_TtlInfo.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_TtlResponse proto.InternalMessageInfo
+var xxx_messageInfo_TtlInfo proto.InternalMessageInfo
 
-func (m *TtlResponse) GetTtl() *duration.Duration {
+func (m *TtlInfo) GetRemaining() *duration.Duration {
 	if m != nil {
-		return m.Ttl
+		return m.Remaining
 	}
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*Empty)(nil), "pb.Empty")
-	proto.RegisterType((*Key)(nil), "pb.Key")
-	proto.RegisterType((*T)(nil), "pb.T")
-	proto.RegisterType((*KeyValue)(nil), "pb.KeyValue")
-	proto.RegisterType((*KeyValueTtl)(nil), "pb.KeyValueTtl")
-	proto.RegisterType((*TtlRequest)(nil), "pb.TtlRequest")
-	proto.RegisterType((*TtlResponse)(nil), "pb.TtlResponse")
+func (m *TtlInfo) GetNoExpiry() bool {
+	if m != nil {
+		return m.NoExpiry
+	}
+	return false
 }
 
-func init() { proto.RegisterFile("cache.proto", fileDescriptor_5fca3b110c9bbf3a) }
+func (m *TtlInfo) GetExpired() *timestamp.Timestamp {
+	if m != nil {
+		return m.Expired
+	}
+	return nil
+}
+
+type ExpireRequest struct {
+	Key                  string             `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Ttl                  *duration.Duration `protobuf:"bytes,2,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	Namespace            string             `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ExpireRequest) Reset()         { *m = ExpireRequest{} }
+func (m *ExpireRequest) String() string { return proto.CompactTextString(m) }
+func (*ExpireRequest) ProtoMessage()    {}
+func (*ExpireRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{11}
+}
+
+func (m *ExpireRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExpireRequest.Unmarshal(m, b)
+}
+func (m *ExpireRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExpireRequest.Marshal(b, m, deterministic)
+}
+func (m *ExpireRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExpireRequest.Merge(m, src)
+}
+func (m *ExpireRequest) XXX_Size() int {
+	return xxx_messageInfo_ExpireRequest.Size(m)
+}
+func (m *ExpireRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExpireRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExpireRequest proto.InternalMessageInfo
+
+func (m *ExpireRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *ExpireRequest) GetTtl() *duration.Duration {
+	if m != nil {
+		return m.Ttl
+	}
+	return nil
+}
+
+func (m *ExpireRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type TtlResponse struct {
+	Ttl                  *duration.Duration `protobuf:"bytes,1,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *TtlResponse) Reset()         { *m = TtlResponse{} }
+func (m *TtlResponse) String() string { return proto.CompactTextString(m) }
+func (*TtlResponse) ProtoMessage()    {}
+func (*TtlResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{12}
+}
+
+func (m *TtlResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TtlResponse.Unmarshal(m, b)
+}
+func (m *TtlResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TtlResponse.Marshal(b, m, deterministic)
+}
+func (m *TtlResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TtlResponse.Merge(m, src)
+}
+func (m *TtlResponse) XXX_Size() int {
+	return xxx_messageInfo_TtlResponse.Size(m)
+}
+func (m *TtlResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TtlResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TtlResponse proto.InternalMessageInfo
+
+func (m *TtlResponse) GetTtl() *duration.Duration {
+	if m != nil {
+		return m.Ttl
+	}
+	return nil
+}
+
+type SetRequest struct {
+	Key   string             `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value *T                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Ttl   *duration.Duration `protobuf:"bytes,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	Mode  WriteMode          `protobuf:"varint,4,opt,name=mode,proto3,enum=pb.WriteMode" json:"mode,omitempty"`
+	// namespace is ignored in the items of MultiSetRequest.
+	Namespace            string   `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetRequest) Reset()         { *m = SetRequest{} }
+func (m *SetRequest) String() string { return proto.CompactTextString(m) }
+func (*SetRequest) ProtoMessage()    {}
+func (*SetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{13}
+}
+
+func (m *SetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetRequest.Unmarshal(m, b)
+}
+func (m *SetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetRequest.Marshal(b, m, deterministic)
+}
+func (m *SetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetRequest.Merge(m, src)
+}
+func (m *SetRequest) XXX_Size() int {
+	return xxx_messageInfo_SetRequest.Size(m)
+}
+func (m *SetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetRequest proto.InternalMessageInfo
+
+func (m *SetRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *SetRequest) GetValue() *T {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *SetRequest) GetTtl() *duration.Duration {
+	if m != nil {
+		return m.Ttl
+	}
+	return nil
+}
+
+func (m *SetRequest) GetMode() WriteMode {
+	if m != nil {
+		return m.Mode
+	}
+	return WriteMode_ALWAYS
+}
+
+func (m *SetRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type SetResponse struct {
+	Written              bool     `protobuf:"varint,1,opt,name=written,proto3" json:"written,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetResponse) Reset()         { *m = SetResponse{} }
+func (m *SetResponse) String() string { return proto.CompactTextString(m) }
+func (*SetResponse) ProtoMessage()    {}
+func (*SetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{14}
+}
+
+func (m *SetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetResponse.Unmarshal(m, b)
+}
+func (m *SetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetResponse.Marshal(b, m, deterministic)
+}
+func (m *SetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetResponse.Merge(m, src)
+}
+func (m *SetResponse) XXX_Size() int {
+	return xxx_messageInfo_SetResponse.Size(m)
+}
+func (m *SetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetResponse proto.InternalMessageInfo
+
+func (m *SetResponse) GetWritten() bool {
+	if m != nil {
+		return m.Written
+	}
+	return false
+}
+
+type CasRequest struct {
+	Key                  string             `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Version              uint64             `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Value                *T                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Ttl                  *duration.Duration `protobuf:"bytes,4,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	Namespace            string             `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *CasRequest) Reset()         { *m = CasRequest{} }
+func (m *CasRequest) String() string { return proto.CompactTextString(m) }
+func (*CasRequest) ProtoMessage()    {}
+func (*CasRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{15}
+}
+
+func (m *CasRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CasRequest.Unmarshal(m, b)
+}
+func (m *CasRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CasRequest.Marshal(b, m, deterministic)
+}
+func (m *CasRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CasRequest.Merge(m, src)
+}
+func (m *CasRequest) XXX_Size() int {
+	return xxx_messageInfo_CasRequest.Size(m)
+}
+func (m *CasRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CasRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CasRequest proto.InternalMessageInfo
+
+func (m *CasRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *CasRequest) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *CasRequest) GetValue() *T {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *CasRequest) GetTtl() *duration.Duration {
+	if m != nil {
+		return m.Ttl
+	}
+	return nil
+}
+
+func (m *CasRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type CasResponse struct {
+	Version              uint64   `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CasResponse) Reset()         { *m = CasResponse{} }
+func (m *CasResponse) String() string { return proto.CompactTextString(m) }
+func (*CasResponse) ProtoMessage()    {}
+func (*CasResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{16}
+}
+
+func (m *CasResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CasResponse.Unmarshal(m, b)
+}
+func (m *CasResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CasResponse.Marshal(b, m, deterministic)
+}
+func (m *CasResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CasResponse.Merge(m, src)
+}
+func (m *CasResponse) XXX_Size() int {
+	return xxx_messageInfo_CasResponse.Size(m)
+}
+func (m *CasResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CasResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CasResponse proto.InternalMessageInfo
+
+func (m *CasResponse) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type ScanRequest struct {
+	Cursor               string   `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Count                int32    `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Prefix               string   `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Match                string   `protobuf:"bytes,4,opt,name=match,proto3" json:"match,omitempty"`
+	Namespace            string   `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ScanRequest) Reset()         { *m = ScanRequest{} }
+func (m *ScanRequest) String() string { return proto.CompactTextString(m) }
+func (*ScanRequest) ProtoMessage()    {}
+func (*ScanRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{17}
+}
+
+func (m *ScanRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScanRequest.Unmarshal(m, b)
+}
+func (m *ScanRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScanRequest.Marshal(b, m, deterministic)
+}
+func (m *ScanRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScanRequest.Merge(m, src)
+}
+func (m *ScanRequest) XXX_Size() int {
+	return xxx_messageInfo_ScanRequest.Size(m)
+}
+func (m *ScanRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScanRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScanRequest proto.InternalMessageInfo
+
+func (m *ScanRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *ScanRequest) GetCount() int32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *ScanRequest) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *ScanRequest) GetMatch() string {
+	if m != nil {
+		return m.Match
+	}
+	return ""
+}
+
+func (m *ScanRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type Item struct {
+	Key                  string               `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value                *T                   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Expired              *timestamp.Timestamp `protobuf:"bytes,4,opt,name=expired,proto3" json:"expired,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Item) Reset()         { *m = Item{} }
+func (m *Item) String() string { return proto.CompactTextString(m) }
+func (*Item) ProtoMessage()    {}
+func (*Item) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{18}
+}
+
+func (m *Item) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Item.Unmarshal(m, b)
+}
+func (m *Item) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Item.Marshal(b, m, deterministic)
+}
+func (m *Item) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Item.Merge(m, src)
+}
+func (m *Item) XXX_Size() int {
+	return xxx_messageInfo_Item.Size(m)
+}
+func (m *Item) XXX_DiscardUnknown() {
+	xxx_messageInfo_Item.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Item proto.InternalMessageInfo
+
+func (m *Item) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *Item) GetValue() *T {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *Item) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *Item) GetExpired() *timestamp.Timestamp {
+	if m != nil {
+		return m.Expired
+	}
+	return nil
+}
+
+type ScanResponse struct {
+	Items                []*Item  `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	NextCursor           string   `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ScanResponse) Reset()         { *m = ScanResponse{} }
+func (m *ScanResponse) String() string { return proto.CompactTextString(m) }
+func (*ScanResponse) ProtoMessage()    {}
+func (*ScanResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{19}
+}
+
+func (m *ScanResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ScanResponse.Unmarshal(m, b)
+}
+func (m *ScanResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ScanResponse.Marshal(b, m, deterministic)
+}
+func (m *ScanResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ScanResponse.Merge(m, src)
+}
+func (m *ScanResponse) XXX_Size() int {
+	return xxx_messageInfo_ScanResponse.Size(m)
+}
+func (m *ScanResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ScanResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ScanResponse proto.InternalMessageInfo
+
+func (m *ScanResponse) GetItems() []*Item {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+func (m *ScanResponse) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+type Keys struct {
+	Keys                 []string `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	Namespace            string   `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Keys) Reset()         { *m = Keys{} }
+func (m *Keys) String() string { return proto.CompactTextString(m) }
+func (*Keys) ProtoMessage()    {}
+func (*Keys) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{20}
+}
+
+func (m *Keys) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Keys.Unmarshal(m, b)
+}
+func (m *Keys) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Keys.Marshal(b, m, deterministic)
+}
+func (m *Keys) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Keys.Merge(m, src)
+}
+func (m *Keys) XXX_Size() int {
+	return xxx_messageInfo_Keys.Size(m)
+}
+func (m *Keys) XXX_DiscardUnknown() {
+	xxx_messageInfo_Keys.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Keys proto.InternalMessageInfo
+
+func (m *Keys) GetKeys() []string {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+func (m *Keys) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type GetResult struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Found                bool     `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	Value                *T       `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetResult) Reset()         { *m = GetResult{} }
+func (m *GetResult) String() string { return proto.CompactTextString(m) }
+func (*GetResult) ProtoMessage()    {}
+func (*GetResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{21}
+}
+
+func (m *GetResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetResult.Unmarshal(m, b)
+}
+func (m *GetResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetResult.Marshal(b, m, deterministic)
+}
+func (m *GetResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetResult.Merge(m, src)
+}
+func (m *GetResult) XXX_Size() int {
+	return xxx_messageInfo_GetResult.Size(m)
+}
+func (m *GetResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetResult proto.InternalMessageInfo
+
+func (m *GetResult) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *GetResult) GetFound() bool {
+	if m != nil {
+		return m.Found
+	}
+	return false
+}
+
+func (m *GetResult) GetValue() *T {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+type MultiGetResponse struct {
+	Results              []*GetResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *MultiGetResponse) Reset()         { *m = MultiGetResponse{} }
+func (m *MultiGetResponse) String() string { return proto.CompactTextString(m) }
+func (*MultiGetResponse) ProtoMessage()    {}
+func (*MultiGetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{22}
+}
+
+func (m *MultiGetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MultiGetResponse.Unmarshal(m, b)
+}
+func (m *MultiGetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MultiGetResponse.Marshal(b, m, deterministic)
+}
+func (m *MultiGetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MultiGetResponse.Merge(m, src)
+}
+func (m *MultiGetResponse) XXX_Size() int {
+	return xxx_messageInfo_MultiGetResponse.Size(m)
+}
+func (m *MultiGetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MultiGetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MultiGetResponse proto.InternalMessageInfo
+
+func (m *MultiGetResponse) GetResults() []*GetResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type MultiSetRequest struct {
+	Items                []*SetRequest `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Namespace            string        `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *MultiSetRequest) Reset()         { *m = MultiSetRequest{} }
+func (m *MultiSetRequest) String() string { return proto.CompactTextString(m) }
+func (*MultiSetRequest) ProtoMessage()    {}
+func (*MultiSetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{23}
+}
+
+func (m *MultiSetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MultiSetRequest.Unmarshal(m, b)
+}
+func (m *MultiSetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MultiSetRequest.Marshal(b, m, deterministic)
+}
+func (m *MultiSetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MultiSetRequest.Merge(m, src)
+}
+func (m *MultiSetRequest) XXX_Size() int {
+	return xxx_messageInfo_MultiSetRequest.Size(m)
+}
+func (m *MultiSetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MultiSetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MultiSetRequest proto.InternalMessageInfo
+
+func (m *MultiSetRequest) GetItems() []*SetRequest {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+func (m *MultiSetRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type SetResult struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Written              bool     `protobuf:"varint,2,opt,name=written,proto3" json:"written,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetResult) Reset()         { *m = SetResult{} }
+func (m *SetResult) String() string { return proto.CompactTextString(m) }
+func (*SetResult) ProtoMessage()    {}
+func (*SetResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{24}
+}
+
+func (m *SetResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetResult.Unmarshal(m, b)
+}
+func (m *SetResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetResult.Marshal(b, m, deterministic)
+}
+func (m *SetResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetResult.Merge(m, src)
+}
+func (m *SetResult) XXX_Size() int {
+	return xxx_messageInfo_SetResult.Size(m)
+}
+func (m *SetResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetResult proto.InternalMessageInfo
+
+func (m *SetResult) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *SetResult) GetWritten() bool {
+	if m != nil {
+		return m.Written
+	}
+	return false
+}
+
+type MultiSetResponse struct {
+	Results              []*SetResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *MultiSetResponse) Reset()         { *m = MultiSetResponse{} }
+func (m *MultiSetResponse) String() string { return proto.CompactTextString(m) }
+func (*MultiSetResponse) ProtoMessage()    {}
+func (*MultiSetResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{25}
+}
+
+func (m *MultiSetResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MultiSetResponse.Unmarshal(m, b)
+}
+func (m *MultiSetResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MultiSetResponse.Marshal(b, m, deterministic)
+}
+func (m *MultiSetResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MultiSetResponse.Merge(m, src)
+}
+func (m *MultiSetResponse) XXX_Size() int {
+	return xxx_messageInfo_MultiSetResponse.Size(m)
+}
+func (m *MultiSetResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MultiSetResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MultiSetResponse proto.InternalMessageInfo
+
+func (m *MultiSetResponse) GetResults() []*SetResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type DeleteResult struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Deleted              bool     `protobuf:"varint,2,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteResult) Reset()         { *m = DeleteResult{} }
+func (m *DeleteResult) String() string { return proto.CompactTextString(m) }
+func (*DeleteResult) ProtoMessage()    {}
+func (*DeleteResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{26}
+}
+
+func (m *DeleteResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteResult.Unmarshal(m, b)
+}
+func (m *DeleteResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteResult.Marshal(b, m, deterministic)
+}
+func (m *DeleteResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteResult.Merge(m, src)
+}
+func (m *DeleteResult) XXX_Size() int {
+	return xxx_messageInfo_DeleteResult.Size(m)
+}
+func (m *DeleteResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteResult proto.InternalMessageInfo
+
+func (m *DeleteResult) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *DeleteResult) GetDeleted() bool {
+	if m != nil {
+		return m.Deleted
+	}
+	return false
+}
+
+type MultiDeleteResponse struct {
+	Results              []*DeleteResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *MultiDeleteResponse) Reset()         { *m = MultiDeleteResponse{} }
+func (m *MultiDeleteResponse) String() string { return proto.CompactTextString(m) }
+func (*MultiDeleteResponse) ProtoMessage()    {}
+func (*MultiDeleteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{27}
+}
+
+func (m *MultiDeleteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MultiDeleteResponse.Unmarshal(m, b)
+}
+func (m *MultiDeleteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MultiDeleteResponse.Marshal(b, m, deterministic)
+}
+func (m *MultiDeleteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MultiDeleteResponse.Merge(m, src)
+}
+func (m *MultiDeleteResponse) XXX_Size() int {
+	return xxx_messageInfo_MultiDeleteResponse.Size(m)
+}
+func (m *MultiDeleteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MultiDeleteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MultiDeleteResponse proto.InternalMessageInfo
+
+func (m *MultiDeleteResponse) GetResults() []*DeleteResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type IncrRequest struct {
+	Key   string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Delta int64  `protobuf:"varint,2,opt,name=delta,proto3" json:"delta,omitempty"`
+	// ttl is applied to the created key, or to an existing one with reset_ttl.
+	Ttl *duration.Duration `protobuf:"bytes,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// reset_ttl replaces the ttl of an existing key with the given one, the key doesn't expire without it.
+	ResetTtl             bool     `protobuf:"varint,4,opt,name=reset_ttl,json=resetTtl,proto3" json:"reset_ttl,omitempty"`
+	Namespace            string   `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IncrRequest) Reset()         { *m = IncrRequest{} }
+func (m *IncrRequest) String() string { return proto.CompactTextString(m) }
+func (*IncrRequest) ProtoMessage()    {}
+func (*IncrRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{28}
+}
+
+func (m *IncrRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IncrRequest.Unmarshal(m, b)
+}
+func (m *IncrRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IncrRequest.Marshal(b, m, deterministic)
+}
+func (m *IncrRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IncrRequest.Merge(m, src)
+}
+func (m *IncrRequest) XXX_Size() int {
+	return xxx_messageInfo_IncrRequest.Size(m)
+}
+func (m *IncrRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_IncrRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IncrRequest proto.InternalMessageInfo
+
+func (m *IncrRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *IncrRequest) GetDelta() int64 {
+	if m != nil {
+		return m.Delta
+	}
+	return 0
+}
+
+func (m *IncrRequest) GetTtl() *duration.Duration {
+	if m != nil {
+		return m.Ttl
+	}
+	return nil
+}
+
+func (m *IncrRequest) GetResetTtl() bool {
+	if m != nil {
+		return m.ResetTtl
+	}
+	return false
+}
+
+func (m *IncrRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type IncrResponse struct {
+	Value                int64    `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IncrResponse) Reset()         { *m = IncrResponse{} }
+func (m *IncrResponse) String() string { return proto.CompactTextString(m) }
+func (*IncrResponse) ProtoMessage()    {}
+func (*IncrResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{29}
+}
+
+func (m *IncrResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IncrResponse.Unmarshal(m, b)
+}
+func (m *IncrResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IncrResponse.Marshal(b, m, deterministic)
+}
+func (m *IncrResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IncrResponse.Merge(m, src)
+}
+func (m *IncrResponse) XXX_Size() int {
+	return xxx_messageInfo_IncrResponse.Size(m)
+}
+func (m *IncrResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_IncrResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IncrResponse proto.InternalMessageInfo
+
+func (m *IncrResponse) GetValue() int64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+type TxnCheck struct {
+	Key                  string        `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Kind                 TxnCheck_Kind `protobuf:"varint,2,opt,name=kind,proto3,enum=pb.TxnCheck_Kind" json:"kind,omitempty"`
+	Version              uint64        `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *TxnCheck) Reset()         { *m = TxnCheck{} }
+func (m *TxnCheck) String() string { return proto.CompactTextString(m) }
+func (*TxnCheck) ProtoMessage()    {}
+func (*TxnCheck) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{30}
+}
+
+func (m *TxnCheck) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxnCheck.Unmarshal(m, b)
+}
+func (m *TxnCheck) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxnCheck.Marshal(b, m, deterministic)
+}
+func (m *TxnCheck) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxnCheck.Merge(m, src)
+}
+func (m *TxnCheck) XXX_Size() int {
+	return xxx_messageInfo_TxnCheck.Size(m)
+}
+func (m *TxnCheck) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxnCheck.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxnCheck proto.InternalMessageInfo
+
+func (m *TxnCheck) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *TxnCheck) GetKind() TxnCheck_Kind {
+	if m != nil {
+		return m.Kind
+	}
+	return TxnCheck_VERSION
+}
+
+func (m *TxnCheck) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type TxnOp struct {
+	Kind                 TxnOp_Kind         `protobuf:"varint,1,opt,name=kind,proto3,enum=pb.TxnOp_Kind" json:"kind,omitempty"`
+	Key                  string             `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Value                *T                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Ttl                  *duration.Duration `protobuf:"bytes,4,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *TxnOp) Reset()         { *m = TxnOp{} }
+func (m *TxnOp) String() string { return proto.CompactTextString(m) }
+func (*TxnOp) ProtoMessage()    {}
+func (*TxnOp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{31}
+}
+
+func (m *TxnOp) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxnOp.Unmarshal(m, b)
+}
+func (m *TxnOp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxnOp.Marshal(b, m, deterministic)
+}
+func (m *TxnOp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxnOp.Merge(m, src)
+}
+func (m *TxnOp) XXX_Size() int {
+	return xxx_messageInfo_TxnOp.Size(m)
+}
+func (m *TxnOp) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxnOp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxnOp proto.InternalMessageInfo
+
+func (m *TxnOp) GetKind() TxnOp_Kind {
+	if m != nil {
+		return m.Kind
+	}
+	return TxnOp_SET
+}
+
+func (m *TxnOp) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *TxnOp) GetValue() *T {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *TxnOp) GetTtl() *duration.Duration {
+	if m != nil {
+		return m.Ttl
+	}
+	return nil
+}
+
+// TxnRequest applies the ops all together if all the checks pass, or none of them.
+type TxnRequest struct {
+	Checks               []*TxnCheck `protobuf:"bytes,1,rep,name=checks,proto3" json:"checks,omitempty"`
+	Ops                  []*TxnOp    `protobuf:"bytes,2,rep,name=ops,proto3" json:"ops,omitempty"`
+	Namespace            string      `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *TxnRequest) Reset()         { *m = TxnRequest{} }
+func (m *TxnRequest) String() string { return proto.CompactTextString(m) }
+func (*TxnRequest) ProtoMessage()    {}
+func (*TxnRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{32}
+}
+
+func (m *TxnRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxnRequest.Unmarshal(m, b)
+}
+func (m *TxnRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxnRequest.Marshal(b, m, deterministic)
+}
+func (m *TxnRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxnRequest.Merge(m, src)
+}
+func (m *TxnRequest) XXX_Size() int {
+	return xxx_messageInfo_TxnRequest.Size(m)
+}
+func (m *TxnRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxnRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxnRequest proto.InternalMessageInfo
+
+func (m *TxnRequest) GetChecks() []*TxnCheck {
+	if m != nil {
+		return m.Checks
+	}
+	return nil
+}
+
+func (m *TxnRequest) GetOps() []*TxnOp {
+	if m != nil {
+		return m.Ops
+	}
+	return nil
+}
+
+func (m *TxnRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type TxnResponse struct {
+	// committed is false if a check failed, the ops are not applied then.
+	Committed bool `protobuf:"varint,1,opt,name=committed,proto3" json:"committed,omitempty"`
+	// failed_check is the index of the first failed check.
+	FailedCheck          int32    `protobuf:"varint,2,opt,name=failed_check,json=failedCheck,proto3" json:"failed_check,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TxnResponse) Reset()         { *m = TxnResponse{} }
+func (m *TxnResponse) String() string { return proto.CompactTextString(m) }
+func (*TxnResponse) ProtoMessage()    {}
+func (*TxnResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{33}
+}
+
+func (m *TxnResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxnResponse.Unmarshal(m, b)
+}
+func (m *TxnResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxnResponse.Marshal(b, m, deterministic)
+}
+func (m *TxnResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxnResponse.Merge(m, src)
+}
+func (m *TxnResponse) XXX_Size() int {
+	return xxx_messageInfo_TxnResponse.Size(m)
+}
+func (m *TxnResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxnResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxnResponse proto.InternalMessageInfo
+
+func (m *TxnResponse) GetCommitted() bool {
+	if m != nil {
+		return m.Committed
+	}
+	return false
+}
+
+func (m *TxnResponse) GetFailedCheck() int32 {
+	if m != nil {
+		return m.FailedCheck
+	}
+	return 0
+}
+
+type LockRequest struct {
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Owner string `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	// token is the fencing token returned by Lock, required by Renew and Unlock.
+	Token uint64 `protobuf:"varint,3,opt,name=token,proto3" json:"token,omitempty"`
+	// ttl is required by Lock and Renew.
+	Ttl                  *duration.Duration `protobuf:"bytes,4,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	Namespace            string             `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *LockRequest) Reset()         { *m = LockRequest{} }
+func (m *LockRequest) String() string { return proto.CompactTextString(m) }
+func (*LockRequest) ProtoMessage()    {}
+func (*LockRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{34}
+}
+
+func (m *LockRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LockRequest.Unmarshal(m, b)
+}
+func (m *LockRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LockRequest.Marshal(b, m, deterministic)
+}
+func (m *LockRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LockRequest.Merge(m, src)
+}
+func (m *LockRequest) XXX_Size() int {
+	return xxx_messageInfo_LockRequest.Size(m)
+}
+func (m *LockRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LockRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LockRequest proto.InternalMessageInfo
+
+func (m *LockRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *LockRequest) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *LockRequest) GetToken() uint64 {
+	if m != nil {
+		return m.Token
+	}
+	return 0
+}
+
+func (m *LockRequest) GetTtl() *duration.Duration {
+	if m != nil {
+		return m.Ttl
+	}
+	return nil
+}
+
+func (m *LockRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type LockResponse struct {
+	Token                uint64   `protobuf:"varint,1,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LockResponse) Reset()         { *m = LockResponse{} }
+func (m *LockResponse) String() string { return proto.CompactTextString(m) }
+func (*LockResponse) ProtoMessage()    {}
+func (*LockResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{35}
+}
+
+func (m *LockResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LockResponse.Unmarshal(m, b)
+}
+func (m *LockResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LockResponse.Marshal(b, m, deterministic)
+}
+func (m *LockResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LockResponse.Merge(m, src)
+}
+func (m *LockResponse) XXX_Size() int {
+	return xxx_messageInfo_LockResponse.Size(m)
+}
+func (m *LockResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LockResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LockResponse proto.InternalMessageInfo
+
+func (m *LockResponse) GetToken() uint64 {
+	if m != nil {
+		return m.Token
+	}
+	return 0
+}
+
+type WatchRequest struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Prefix               string   `protobuf:"bytes,2,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Namespace            string   `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchRequest) Reset()         { *m = WatchRequest{} }
+func (m *WatchRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchRequest) ProtoMessage()    {}
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{36}
+}
+
+func (m *WatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchRequest.Unmarshal(m, b)
+}
+func (m *WatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRequest.Merge(m, src)
+}
+func (m *WatchRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchRequest.Size(m)
+}
+func (m *WatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRequest proto.InternalMessageInfo
+
+func (m *WatchRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *WatchRequest) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *WatchRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+type Event struct {
+	Type                 EventType            `protobuf:"varint,1,opt,name=type,proto3,enum=pb.EventType" json:"type,omitempty"`
+	Key                  string               `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Value                *T                   `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Expired              *timestamp.Timestamp `protobuf:"bytes,4,opt,name=expired,proto3" json:"expired,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Event) Reset()         { *m = Event{} }
+func (m *Event) String() string { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()    {}
+func (*Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{37}
+}
+
+func (m *Event) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Event.Unmarshal(m, b)
+}
+func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+}
+func (m *Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Event.Merge(m, src)
+}
+func (m *Event) XXX_Size() int {
+	return xxx_messageInfo_Event.Size(m)
+}
+func (m *Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Event proto.InternalMessageInfo
+
+func (m *Event) GetType() EventType {
+	if m != nil {
+		return m.Type
+	}
+	return EventType_SET
+}
+
+func (m *Event) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *Event) GetValue() *T {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *Event) GetExpired() *timestamp.Timestamp {
+	if m != nil {
+		return m.Expired
+	}
+	return nil
+}
+
+// Entry is the full state of a key sent to replication followers.
+type Entry struct {
+	Key                  string               `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value                *T                   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Expired              *timestamp.Timestamp `protobuf:"bytes,4,opt,name=expired,proto3" json:"expired,omitempty"`
+	Sliding              *duration.Duration   `protobuf:"bytes,5,opt,name=sliding,proto3" json:"sliding,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Entry) Reset()         { *m = Entry{} }
+func (m *Entry) String() string { return proto.CompactTextString(m) }
+func (*Entry) ProtoMessage()    {}
+func (*Entry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{38}
+}
+
+func (m *Entry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Entry.Unmarshal(m, b)
+}
+func (m *Entry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Entry.Marshal(b, m, deterministic)
+}
+func (m *Entry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Entry.Merge(m, src)
+}
+func (m *Entry) XXX_Size() int {
+	return xxx_messageInfo_Entry.Size(m)
+}
+func (m *Entry) XXX_DiscardUnknown() {
+	xxx_messageInfo_Entry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Entry proto.InternalMessageInfo
+
+func (m *Entry) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *Entry) GetValue() *T {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *Entry) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *Entry) GetExpired() *timestamp.Timestamp {
+	if m != nil {
+		return m.Expired
+	}
+	return nil
+}
+
+func (m *Entry) GetSliding() *duration.Duration {
+	if m != nil {
+		return m.Sliding
+	}
+	return nil
+}
+
+// ReplicationMessage is streamed by the leader to a follower. Every namespace starts with
+// NAMESPACE_CREATE followed by the entries of its snapshot and SNAPSHOT_END, then the events
+// of its changes until NAMESPACE_DROP. SYNCED follows the snapshots of the namespaces existing
+// when the stream starts. Heartbeats are sent when the leader is idle.
+type ReplicationMessage struct {
+	Kind  ReplicationMessage_Kind `protobuf:"varint,1,opt,name=kind,proto3,enum=pb.ReplicationMessage_Kind" json:"kind,omitempty"`
+	Event EventType               `protobuf:"varint,2,opt,name=event,proto3,enum=pb.EventType" json:"event,omitempty"`
+	Entry *Entry                  `protobuf:"bytes,3,opt,name=entry,proto3" json:"entry,omitempty"`
+	// time is the moment of the change, or of sending for the other kinds, by the leader clock.
+	Time *timestamp.Timestamp `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+	// namespace is set for all the kinds except HEARTBEAT and SYNCED.
+	Namespace string `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// namespace_info is set for NAMESPACE_CREATE.
+	NamespaceInfo        *NamespaceInfo `protobuf:"bytes,6,opt,name=namespace_info,json=namespaceInfo,proto3" json:"namespace_info,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ReplicationMessage) Reset()         { *m = ReplicationMessage{} }
+func (m *ReplicationMessage) String() string { return proto.CompactTextString(m) }
+func (*ReplicationMessage) ProtoMessage()    {}
+func (*ReplicationMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{39}
+}
+
+func (m *ReplicationMessage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplicationMessage.Unmarshal(m, b)
+}
+func (m *ReplicationMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplicationMessage.Marshal(b, m, deterministic)
+}
+func (m *ReplicationMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplicationMessage.Merge(m, src)
+}
+func (m *ReplicationMessage) XXX_Size() int {
+	return xxx_messageInfo_ReplicationMessage.Size(m)
+}
+func (m *ReplicationMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplicationMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplicationMessage proto.InternalMessageInfo
+
+func (m *ReplicationMessage) GetKind() ReplicationMessage_Kind {
+	if m != nil {
+		return m.Kind
+	}
+	return ReplicationMessage_ENTRY
+}
+
+func (m *ReplicationMessage) GetEvent() EventType {
+	if m != nil {
+		return m.Event
+	}
+	return EventType_SET
+}
+
+func (m *ReplicationMessage) GetEntry() *Entry {
+	if m != nil {
+		return m.Entry
+	}
+	return nil
+}
+
+func (m *ReplicationMessage) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *ReplicationMessage) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ReplicationMessage) GetNamespaceInfo() *NamespaceInfo {
+	if m != nil {
+		return m.NamespaceInfo
+	}
+	return nil
+}
+
+type ReplicationInfo struct {
+	Role ReplicationInfo_Role `protobuf:"varint,1,opt,name=role,proto3,enum=pb.ReplicationInfo_Role" json:"role,omitempty"`
+	// leader is the address of the leader, set for followers only.
+	Leader    string `protobuf:"bytes,2,opt,name=leader,proto3" json:"leader,omitempty"`
+	Connected bool   `protobuf:"varint,3,opt,name=connected,proto3" json:"connected,omitempty"`
+	// lag is the time since the last change applied by the follower was made on the leader,
+	// or since the last heartbeat if the follower is up to date.
+	Lag                  *duration.Duration `protobuf:"bytes,4,opt,name=lag,proto3" json:"lag,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ReplicationInfo) Reset()         { *m = ReplicationInfo{} }
+func (m *ReplicationInfo) String() string { return proto.CompactTextString(m) }
+func (*ReplicationInfo) ProtoMessage()    {}
+func (*ReplicationInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{40}
+}
+
+func (m *ReplicationInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplicationInfo.Unmarshal(m, b)
+}
+func (m *ReplicationInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplicationInfo.Marshal(b, m, deterministic)
+}
+func (m *ReplicationInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplicationInfo.Merge(m, src)
+}
+func (m *ReplicationInfo) XXX_Size() int {
+	return xxx_messageInfo_ReplicationInfo.Size(m)
+}
+func (m *ReplicationInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplicationInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplicationInfo proto.InternalMessageInfo
+
+func (m *ReplicationInfo) GetRole() ReplicationInfo_Role {
+	if m != nil {
+		return m.Role
+	}
+	return ReplicationInfo_LEADER
+}
+
+func (m *ReplicationInfo) GetLeader() string {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
+}
+
+func (m *ReplicationInfo) GetConnected() bool {
+	if m != nil {
+		return m.Connected
+	}
+	return false
+}
+
+func (m *ReplicationInfo) GetLag() *duration.Duration {
+	if m != nil {
+		return m.Lag
+	}
+	return nil
+}
+
+type Member struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// address is the gRPC address of the node, not needed by RemoveMember.
+	Address              string   `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Member) Reset()         { *m = Member{} }
+func (m *Member) String() string { return proto.CompactTextString(m) }
+func (*Member) ProtoMessage()    {}
+func (*Member) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{41}
+}
+
+func (m *Member) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Member.Unmarshal(m, b)
+}
+func (m *Member) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Member.Marshal(b, m, deterministic)
+}
+func (m *Member) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Member.Merge(m, src)
+}
+func (m *Member) XXX_Size() int {
+	return xxx_messageInfo_Member.Size(m)
+}
+func (m *Member) XXX_DiscardUnknown() {
+	xxx_messageInfo_Member.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Member proto.InternalMessageInfo
+
+func (m *Member) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Member) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+type MemberList struct {
+	Members []*Member `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
+	// leader is the id of the current leader if it is known.
+	Leader               string   `protobuf:"bytes,2,opt,name=leader,proto3" json:"leader,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MemberList) Reset()         { *m = MemberList{} }
+func (m *MemberList) String() string { return proto.CompactTextString(m) }
+func (*MemberList) ProtoMessage()    {}
+func (*MemberList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{42}
+}
+
+func (m *MemberList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MemberList.Unmarshal(m, b)
+}
+func (m *MemberList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MemberList.Marshal(b, m, deterministic)
+}
+func (m *MemberList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MemberList.Merge(m, src)
+}
+func (m *MemberList) XXX_Size() int {
+	return xxx_messageInfo_MemberList.Size(m)
+}
+func (m *MemberList) XXX_DiscardUnknown() {
+	xxx_messageInfo_MemberList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MemberList proto.InternalMessageInfo
+
+func (m *MemberList) GetMembers() []*Member {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+func (m *MemberList) GetLeader() string {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
+}
+
+type RaftEntry struct {
+	Index                uint64         `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Term                 uint64         `protobuf:"varint,2,opt,name=term,proto3" json:"term,omitempty"`
+	Kind                 RaftEntry_Kind `protobuf:"varint,3,opt,name=kind,proto3,enum=pb.RaftEntry_Kind" json:"kind,omitempty"`
+	Data                 []byte         `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *RaftEntry) Reset()         { *m = RaftEntry{} }
+func (m *RaftEntry) String() string { return proto.CompactTextString(m) }
+func (*RaftEntry) ProtoMessage()    {}
+func (*RaftEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{43}
+}
+
+func (m *RaftEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RaftEntry.Unmarshal(m, b)
+}
+func (m *RaftEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RaftEntry.Marshal(b, m, deterministic)
+}
+func (m *RaftEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RaftEntry.Merge(m, src)
+}
+func (m *RaftEntry) XXX_Size() int {
+	return xxx_messageInfo_RaftEntry.Size(m)
+}
+func (m *RaftEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_RaftEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RaftEntry proto.InternalMessageInfo
+
+func (m *RaftEntry) GetIndex() uint64 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *RaftEntry) GetTerm() uint64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *RaftEntry) GetKind() RaftEntry_Kind {
+	if m != nil {
+		return m.Kind
+	}
+	return RaftEntry_NOOP
+}
+
+func (m *RaftEntry) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type VoteRequest struct {
+	Term                 uint64   `protobuf:"varint,1,opt,name=term,proto3" json:"term,omitempty"`
+	Candidate            string   `protobuf:"bytes,2,opt,name=candidate,proto3" json:"candidate,omitempty"`
+	LastLogIndex         uint64   `protobuf:"varint,3,opt,name=last_log_index,json=lastLogIndex,proto3" json:"last_log_index,omitempty"`
+	LastLogTerm          uint64   `protobuf:"varint,4,opt,name=last_log_term,json=lastLogTerm,proto3" json:"last_log_term,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VoteRequest) Reset()         { *m = VoteRequest{} }
+func (m *VoteRequest) String() string { return proto.CompactTextString(m) }
+func (*VoteRequest) ProtoMessage()    {}
+func (*VoteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{44}
+}
+
+func (m *VoteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VoteRequest.Unmarshal(m, b)
+}
+func (m *VoteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VoteRequest.Marshal(b, m, deterministic)
+}
+func (m *VoteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VoteRequest.Merge(m, src)
+}
+func (m *VoteRequest) XXX_Size() int {
+	return xxx_messageInfo_VoteRequest.Size(m)
+}
+func (m *VoteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VoteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VoteRequest proto.InternalMessageInfo
+
+func (m *VoteRequest) GetTerm() uint64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *VoteRequest) GetCandidate() string {
+	if m != nil {
+		return m.Candidate
+	}
+	return ""
+}
+
+func (m *VoteRequest) GetLastLogIndex() uint64 {
+	if m != nil {
+		return m.LastLogIndex
+	}
+	return 0
+}
+
+func (m *VoteRequest) GetLastLogTerm() uint64 {
+	if m != nil {
+		return m.LastLogTerm
+	}
+	return 0
+}
+
+type VoteResponse struct {
+	Term                 uint64   `protobuf:"varint,1,opt,name=term,proto3" json:"term,omitempty"`
+	Granted              bool     `protobuf:"varint,2,opt,name=granted,proto3" json:"granted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VoteResponse) Reset()         { *m = VoteResponse{} }
+func (m *VoteResponse) String() string { return proto.CompactTextString(m) }
+func (*VoteResponse) ProtoMessage()    {}
+func (*VoteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{45}
+}
+
+func (m *VoteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VoteResponse.Unmarshal(m, b)
+}
+func (m *VoteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VoteResponse.Marshal(b, m, deterministic)
+}
+func (m *VoteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VoteResponse.Merge(m, src)
+}
+func (m *VoteResponse) XXX_Size() int {
+	return xxx_messageInfo_VoteResponse.Size(m)
+}
+func (m *VoteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_VoteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VoteResponse proto.InternalMessageInfo
+
+func (m *VoteResponse) GetTerm() uint64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *VoteResponse) GetGranted() bool {
+	if m != nil {
+		return m.Granted
+	}
+	return false
+}
+
+type AppendRequest struct {
+	Term                 uint64       `protobuf:"varint,1,opt,name=term,proto3" json:"term,omitempty"`
+	Leader               string       `protobuf:"bytes,2,opt,name=leader,proto3" json:"leader,omitempty"`
+	PrevLogIndex         uint64       `protobuf:"varint,3,opt,name=prev_log_index,json=prevLogIndex,proto3" json:"prev_log_index,omitempty"`
+	PrevLogTerm          uint64       `protobuf:"varint,4,opt,name=prev_log_term,json=prevLogTerm,proto3" json:"prev_log_term,omitempty"`
+	Entries              []*RaftEntry `protobuf:"bytes,5,rep,name=entries,proto3" json:"entries,omitempty"`
+	LeaderCommit         uint64       `protobuf:"varint,6,opt,name=leader_commit,json=leaderCommit,proto3" json:"leader_commit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *AppendRequest) Reset()         { *m = AppendRequest{} }
+func (m *AppendRequest) String() string { return proto.CompactTextString(m) }
+func (*AppendRequest) ProtoMessage()    {}
+func (*AppendRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{46}
+}
+
+func (m *AppendRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppendRequest.Unmarshal(m, b)
+}
+func (m *AppendRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppendRequest.Marshal(b, m, deterministic)
+}
+func (m *AppendRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppendRequest.Merge(m, src)
+}
+func (m *AppendRequest) XXX_Size() int {
+	return xxx_messageInfo_AppendRequest.Size(m)
+}
+func (m *AppendRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppendRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppendRequest proto.InternalMessageInfo
+
+func (m *AppendRequest) GetTerm() uint64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *AppendRequest) GetLeader() string {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
+}
+
+func (m *AppendRequest) GetPrevLogIndex() uint64 {
+	if m != nil {
+		return m.PrevLogIndex
+	}
+	return 0
+}
+
+func (m *AppendRequest) GetPrevLogTerm() uint64 {
+	if m != nil {
+		return m.PrevLogTerm
+	}
+	return 0
+}
+
+func (m *AppendRequest) GetEntries() []*RaftEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+func (m *AppendRequest) GetLeaderCommit() uint64 {
+	if m != nil {
+		return m.LeaderCommit
+	}
+	return 0
+}
+
+type AppendResponse struct {
+	Term    uint64 `protobuf:"varint,1,opt,name=term,proto3" json:"term,omitempty"`
+	Success bool   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	// last_log_index is the last index of the follower log, helps the leader to find the match.
+	LastLogIndex         uint64   `protobuf:"varint,3,opt,name=last_log_index,json=lastLogIndex,proto3" json:"last_log_index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AppendResponse) Reset()         { *m = AppendResponse{} }
+func (m *AppendResponse) String() string { return proto.CompactTextString(m) }
+func (*AppendResponse) ProtoMessage()    {}
+func (*AppendResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{47}
+}
+
+func (m *AppendResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppendResponse.Unmarshal(m, b)
+}
+func (m *AppendResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppendResponse.Marshal(b, m, deterministic)
+}
+func (m *AppendResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppendResponse.Merge(m, src)
+}
+func (m *AppendResponse) XXX_Size() int {
+	return xxx_messageInfo_AppendResponse.Size(m)
+}
+func (m *AppendResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppendResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppendResponse proto.InternalMessageInfo
+
+func (m *AppendResponse) GetTerm() uint64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *AppendResponse) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
+}
+
+func (m *AppendResponse) GetLastLogIndex() uint64 {
+	if m != nil {
+		return m.LastLogIndex
+	}
+	return 0
+}
+
+type SnapshotRequest struct {
+	Term      uint64    `protobuf:"varint,1,opt,name=term,proto3" json:"term,omitempty"`
+	Leader    string    `protobuf:"bytes,2,opt,name=leader,proto3" json:"leader,omitempty"`
+	LastIndex uint64    `protobuf:"varint,3,opt,name=last_index,json=lastIndex,proto3" json:"last_index,omitempty"`
+	LastTerm  uint64    `protobuf:"varint,4,opt,name=last_term,json=lastTerm,proto3" json:"last_term,omitempty"`
+	Members   []*Member `protobuf:"bytes,5,rep,name=members,proto3" json:"members,omitempty"`
+	// data is the JSON encoded content of the cache.
+	Data                 []byte   `protobuf:"bytes,6,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotRequest) Reset()         { *m = SnapshotRequest{} }
+func (m *SnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*SnapshotRequest) ProtoMessage()    {}
+func (*SnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{48}
+}
+
+func (m *SnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotRequest.Unmarshal(m, b)
+}
+func (m *SnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *SnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotRequest.Merge(m, src)
+}
+func (m *SnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_SnapshotRequest.Size(m)
+}
+func (m *SnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotRequest proto.InternalMessageInfo
+
+func (m *SnapshotRequest) GetTerm() uint64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func (m *SnapshotRequest) GetLeader() string {
+	if m != nil {
+		return m.Leader
+	}
+	return ""
+}
+
+func (m *SnapshotRequest) GetLastIndex() uint64 {
+	if m != nil {
+		return m.LastIndex
+	}
+	return 0
+}
+
+func (m *SnapshotRequest) GetLastTerm() uint64 {
+	if m != nil {
+		return m.LastTerm
+	}
+	return 0
+}
+
+func (m *SnapshotRequest) GetMembers() []*Member {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+func (m *SnapshotRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type SnapshotResponse struct {
+	Term                 uint64   `protobuf:"varint,1,opt,name=term,proto3" json:"term,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotResponse) Reset()         { *m = SnapshotResponse{} }
+func (m *SnapshotResponse) String() string { return proto.CompactTextString(m) }
+func (*SnapshotResponse) ProtoMessage()    {}
+func (*SnapshotResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fca3b110c9bbf3a, []int{49}
+}
+
+func (m *SnapshotResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotResponse.Unmarshal(m, b)
+}
+func (m *SnapshotResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotResponse.Marshal(b, m, deterministic)
+}
+func (m *SnapshotResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotResponse.Merge(m, src)
+}
+func (m *SnapshotResponse) XXX_Size() int {
+	return xxx_messageInfo_SnapshotResponse.Size(m)
+}
+func (m *SnapshotResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotResponse proto.InternalMessageInfo
+
+func (m *SnapshotResponse) GetTerm() uint64 {
+	if m != nil {
+		return m.Term
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterEnum("pb.WriteMode", WriteMode_name, WriteMode_value)
+	proto.RegisterEnum("pb.EventType", EventType_name, EventType_value)
+	proto.RegisterEnum("pb.TxnCheck_Kind", TxnCheck_Kind_name, TxnCheck_Kind_value)
+	proto.RegisterEnum("pb.TxnOp_Kind", TxnOp_Kind_name, TxnOp_Kind_value)
+	proto.RegisterEnum("pb.ReplicationMessage_Kind", ReplicationMessage_Kind_name, ReplicationMessage_Kind_value)
+	proto.RegisterEnum("pb.ReplicationInfo_Role", ReplicationInfo_Role_name, ReplicationInfo_Role_value)
+	proto.RegisterEnum("pb.RaftEntry_Kind", RaftEntry_Kind_name, RaftEntry_Kind_value)
+	proto.RegisterType((*Empty)(nil), "pb.Empty")
+	proto.RegisterType((*Namespace)(nil), "pb.Namespace")
+	proto.RegisterType((*NamespaceInfo)(nil), "pb.NamespaceInfo")
+	proto.RegisterType((*NamespaceList)(nil), "pb.NamespaceList")
+	proto.RegisterType((*StatsResponse)(nil), "pb.StatsResponse")
+	proto.RegisterType((*Key)(nil), "pb.Key")
+	proto.RegisterType((*T)(nil), "pb.T")
+	proto.RegisterType((*KeyValue)(nil), "pb.KeyValue")
+	proto.RegisterType((*KeyValueTtl)(nil), "pb.KeyValueTtl")
+	proto.RegisterType((*TtlRequest)(nil), "pb.TtlRequest")
+	proto.RegisterType((*TtlInfo)(nil), "pb.TtlInfo")
+	proto.RegisterType((*ExpireRequest)(nil), "pb.ExpireRequest")
+	proto.RegisterType((*TtlResponse)(nil), "pb.TtlResponse")
+	proto.RegisterType((*SetRequest)(nil), "pb.SetRequest")
+	proto.RegisterType((*SetResponse)(nil), "pb.SetResponse")
+	proto.RegisterType((*CasRequest)(nil), "pb.CasRequest")
+	proto.RegisterType((*CasResponse)(nil), "pb.CasResponse")
+	proto.RegisterType((*ScanRequest)(nil), "pb.ScanRequest")
+	proto.RegisterType((*Item)(nil), "pb.Item")
+	proto.RegisterType((*ScanResponse)(nil), "pb.ScanResponse")
+	proto.RegisterType((*Keys)(nil), "pb.Keys")
+	proto.RegisterType((*GetResult)(nil), "pb.GetResult")
+	proto.RegisterType((*MultiGetResponse)(nil), "pb.MultiGetResponse")
+	proto.RegisterType((*MultiSetRequest)(nil), "pb.MultiSetRequest")
+	proto.RegisterType((*SetResult)(nil), "pb.SetResult")
+	proto.RegisterType((*MultiSetResponse)(nil), "pb.MultiSetResponse")
+	proto.RegisterType((*DeleteResult)(nil), "pb.DeleteResult")
+	proto.RegisterType((*MultiDeleteResponse)(nil), "pb.MultiDeleteResponse")
+	proto.RegisterType((*IncrRequest)(nil), "pb.IncrRequest")
+	proto.RegisterType((*IncrResponse)(nil), "pb.IncrResponse")
+	proto.RegisterType((*TxnCheck)(nil), "pb.TxnCheck")
+	proto.RegisterType((*TxnOp)(nil), "pb.TxnOp")
+	proto.RegisterType((*TxnRequest)(nil), "pb.TxnRequest")
+	proto.RegisterType((*TxnResponse)(nil), "pb.TxnResponse")
+	proto.RegisterType((*LockRequest)(nil), "pb.LockRequest")
+	proto.RegisterType((*LockResponse)(nil), "pb.LockResponse")
+	proto.RegisterType((*WatchRequest)(nil), "pb.WatchRequest")
+	proto.RegisterType((*Event)(nil), "pb.Event")
+	proto.RegisterType((*Entry)(nil), "pb.Entry")
+	proto.RegisterType((*ReplicationMessage)(nil), "pb.ReplicationMessage")
+	proto.RegisterType((*ReplicationInfo)(nil), "pb.ReplicationInfo")
+	proto.RegisterType((*Member)(nil), "pb.Member")
+	proto.RegisterType((*MemberList)(nil), "pb.MemberList")
+	proto.RegisterType((*RaftEntry)(nil), "pb.RaftEntry")
+	proto.RegisterType((*VoteRequest)(nil), "pb.VoteRequest")
+	proto.RegisterType((*VoteResponse)(nil), "pb.VoteResponse")
+	proto.RegisterType((*AppendRequest)(nil), "pb.AppendRequest")
+	proto.RegisterType((*AppendResponse)(nil), "pb.AppendResponse")
+	proto.RegisterType((*SnapshotRequest)(nil), "pb.SnapshotRequest")
+	proto.RegisterType((*SnapshotResponse)(nil), "pb.SnapshotResponse")
+}
+
+func init() { proto.RegisterFile("cache.proto", fileDescriptor_5fca3b110c9bbf3a) }
+
+var fileDescriptor_5fca3b110c9bbf3a = []byte{
+	// 2455 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x59, 0x5b, 0x6f, 0xdb, 0xc8,
+	0xf5, 0x37, 0x25, 0x51, 0x97, 0x23, 0x59, 0xe6, 0xce, 0x1a, 0xfb, 0xd7, 0xca, 0xff, 0x4d, 0xbc,
+	0x8c, 0x37, 0x71, 0x93, 0x85, 0x93, 0x75, 0xba, 0x4d, 0x9a, 0x16, 0x05, 0x14, 0x99, 0x49, 0xd4,
+	0xf8, 0x86, 0x21, 0xd7, 0x6e, 0x9e, 0x04, 0x5a, 0x1c, 0xdb, 0x84, 0x29, 0x52, 0x25, 0x47, 0x8e,
+	0xf5, 0x09, 0x7a, 0x79, 0xe9, 0x53, 0x81, 0xbe, 0x2c, 0xd0, 0x02, 0x45, 0x81, 0xbe, 0xf4, 0xa5,
+	0x5f, 0xa1, 0xdf, 0xa0, 0xaf, 0x45, 0x3f, 0x4b, 0x31, 0x17, 0xde, 0x64, 0xc9, 0x72, 0xb6, 0xfb,
+	0xd0, 0x37, 0x9d, 0x33, 0xbf, 0x99, 0x73, 0x99, 0x73, 0x1b, 0x0a, 0xea, 0x03, 0x7b, 0x70, 0x4e,
+	0xb6, 0x46, 0x61, 0x40, 0x03, 0x54, 0x18, 0x9d, 0xb4, 0xef, 0x9c, 0x05, 0xc1, 0x99, 0x47, 0x1e,
+	0x73, 0xce, 0xc9, 0xf8, 0xf4, 0xb1, 0x33, 0x0e, 0x6d, 0xea, 0x06, 0xbe, 0xc0, 0xb4, 0xef, 0x4e,
+	0xaf, 0x53, 0x77, 0x48, 0x22, 0x6a, 0x0f, 0x47, 0x02, 0xa0, 0x57, 0x40, 0x35, 0x86, 0x23, 0x3a,
+	0xd1, 0x7f, 0x00, 0xb5, 0x7d, 0x7b, 0x48, 0xa2, 0x91, 0x3d, 0x20, 0xe8, 0xff, 0xa1, 0xe6, 0xc7,
+	0x44, 0x4b, 0x59, 0x57, 0x36, 0x6b, 0x38, 0x65, 0xe8, 0xdf, 0x2a, 0xb0, 0x9c, 0x60, 0x7b, 0xfe,
+	0x69, 0x80, 0x10, 0x94, 0xd8, 0xb2, 0x84, 0xf2, 0xdf, 0xe8, 0x05, 0xd4, 0x1d, 0x72, 0x6a, 0x8f,
+	0x3d, 0xda, 0xa7, 0xd4, 0x6b, 0x15, 0xd6, 0x95, 0xcd, 0xfa, 0xf6, 0xa7, 0x5b, 0x42, 0xa1, 0xad,
+	0x58, 0xa1, 0xad, 0x1d, 0xa9, 0x30, 0x06, 0x89, 0xb6, 0xa8, 0x87, 0xee, 0x42, 0x7d, 0x68, 0x5f,
+	0xf5, 0x89, 0x4f, 0x43, 0x97, 0x44, 0xad, 0xe2, 0xba, 0xb2, 0x59, 0xc4, 0x30, 0xb4, 0xaf, 0x0c,
+	0xc1, 0x41, 0x6b, 0x50, 0x63, 0x80, 0x93, 0x09, 0x25, 0x51, 0xab, 0xc4, 0x97, 0xab, 0x43, 0xfb,
+	0xea, 0x25, 0xa3, 0xf5, 0x97, 0x19, 0xf5, 0x76, 0xdd, 0x88, 0xa2, 0xaf, 0x00, 0x12, 0xed, 0xa3,
+	0x96, 0xb2, 0x5e, 0xdc, 0xac, 0x6f, 0x7f, 0xb4, 0x35, 0x3a, 0xd9, 0xca, 0x59, 0x81, 0x33, 0x20,
+	0xfd, 0x00, 0x96, 0x4d, 0x6a, 0xd3, 0x08, 0x93, 0x68, 0x14, 0xf8, 0x11, 0x77, 0x09, 0xb9, 0x74,
+	0x07, 0x4c, 0xd5, 0x88, 0xdb, 0x59, 0xc2, 0x29, 0x03, 0xad, 0x43, 0x9d, 0x5c, 0x8d, 0x5c, 0x61,
+	0x4a, 0xc4, 0x8d, 0x2d, 0xe1, 0x2c, 0x4b, 0xff, 0x1a, 0x8a, 0x6f, 0xc9, 0x04, 0x69, 0x50, 0xbc,
+	0x20, 0x13, 0xe9, 0x28, 0xf6, 0x33, 0xef, 0xeb, 0xc2, 0xb4, 0xaf, 0x8f, 0x40, 0xb1, 0xd0, 0x2a,
+	0xa8, 0x97, 0xb6, 0x37, 0x16, 0xfe, 0x6d, 0x60, 0x41, 0xa0, 0x16, 0x54, 0x2e, 0x49, 0x18, 0xb9,
+	0x81, 0x2f, 0xe5, 0xc5, 0x24, 0xfa, 0x1c, 0x1a, 0x83, 0xc0, 0xa7, 0xc4, 0xa7, 0x7d, 0x3a, 0x19,
+	0x11, 0xee, 0xbf, 0x1a, 0xae, 0x4b, 0x9e, 0x35, 0x19, 0x11, 0xfd, 0x18, 0xaa, 0x6f, 0xc9, 0xe4,
+	0x88, 0x1f, 0x74, 0x5d, 0xa7, 0xb5, 0x58, 0xa0, 0xb8, 0x35, 0x95, 0xf9, 0xca, 0x8a, 0xe5, 0xe6,
+	0x14, 0x2e, 0x4e, 0x2b, 0xfc, 0x27, 0x05, 0xea, 0xf1, 0xc9, 0xec, 0x2a, 0x3f, 0xf0, 0xf0, 0x47,
+	0x50, 0x64, 0xd1, 0x52, 0x5c, 0x14, 0x2d, 0x0c, 0xc5, 0x3c, 0x10, 0x79, 0xae, 0xe3, 0xfa, 0x67,
+	0x3c, 0x06, 0xaa, 0x38, 0x26, 0xf3, 0x3a, 0xaa, 0xd3, 0x3a, 0xfa, 0x00, 0x16, 0xf5, 0x30, 0xf9,
+	0xe5, 0x98, 0x44, 0x74, 0x86, 0x86, 0x4f, 0x40, 0xe5, 0x39, 0x22, 0x35, 0x6c, 0x5f, 0x53, 0xc3,
+	0x8a, 0xb3, 0x08, 0x0b, 0xe0, 0x02, 0x9f, 0xfc, 0x5e, 0x81, 0x8a, 0x45, 0x3d, 0x9e, 0x2a, 0xcf,
+	0xa0, 0x16, 0x92, 0xa1, 0xed, 0xfa, 0x4c, 0x6b, 0x65, 0x91, 0x99, 0x29, 0x96, 0x85, 0xbc, 0x1f,
+	0xf4, 0x79, 0x48, 0x4d, 0xb8, 0x62, 0x55, 0x5c, 0xf5, 0x03, 0x83, 0xd3, 0xe8, 0x87, 0x50, 0xe1,
+	0x2b, 0xc4, 0x69, 0x15, 0x17, 0xea, 0x1c, 0x43, 0x75, 0x0f, 0x96, 0xf9, 0x7e, 0x32, 0xdf, 0x15,
+	0xf2, 0x3e, 0x0a, 0xb7, 0xba, 0x8f, 0x9b, 0xbd, 0xf0, 0x02, 0xea, 0xdc, 0xeb, 0x32, 0xa1, 0xe4,
+	0xc9, 0xca, 0x6d, 0x4e, 0xd6, 0xff, 0xaa, 0x00, 0x98, 0x84, 0xce, 0xd7, 0xf3, 0xfb, 0x0b, 0xaa,
+	0xcf, 0xa1, 0x34, 0x0c, 0x1c, 0xc2, 0x23, 0xaa, 0xb9, 0xbd, 0xcc, 0x0e, 0x3a, 0x0e, 0x5d, 0x4a,
+	0xf6, 0x02, 0x87, 0x60, 0xbe, 0xb4, 0x20, 0xba, 0x1e, 0x40, 0x9d, 0xab, 0x2a, 0xed, 0x6c, 0x41,
+	0xe5, 0x7d, 0xe8, 0x52, 0x4a, 0x7c, 0xae, 0x6f, 0x15, 0xc7, 0xa4, 0xfe, 0x47, 0x05, 0xa0, 0x6b,
+	0x47, 0xf3, 0x8d, 0x9a, 0x9f, 0xe1, 0x89, 0xb9, 0xc5, 0xf9, 0xe6, 0x96, 0x3e, 0xfc, 0xce, 0x66,
+	0xd9, 0xc2, 0x35, 0x4c, 0x6d, 0x89, 0x15, 0x52, 0x72, 0x0a, 0xe9, 0xbf, 0x52, 0xa0, 0x6e, 0x0e,
+	0x6c, 0x3f, 0x36, 0xe6, 0x13, 0x28, 0x0f, 0xc6, 0x61, 0x14, 0x84, 0xd2, 0x1e, 0x49, 0xb1, 0x52,
+	0x36, 0x08, 0xc6, 0x3e, 0xe5, 0x06, 0xa9, 0x58, 0x10, 0x0c, 0x3d, 0x0a, 0xc9, 0xa9, 0x7b, 0x25,
+	0xa3, 0x46, 0x52, 0x0c, 0x3d, 0xb4, 0xe9, 0xe0, 0x9c, 0xdb, 0x52, 0xc3, 0x82, 0x58, 0xa0, 0xf2,
+	0x5f, 0x14, 0x28, 0xf5, 0x28, 0x19, 0x7e, 0x68, 0x90, 0xfc, 0x18, 0x60, 0x10, 0x12, 0x9b, 0x12,
+	0xa7, 0x6f, 0xd3, 0x5b, 0x64, 0x51, 0x4d, 0xa2, 0x3b, 0x34, 0x9b, 0x7d, 0xa5, 0xdb, 0x67, 0xdf,
+	0x01, 0x34, 0x84, 0xc7, 0xa4, 0x73, 0xef, 0x80, 0xea, 0x52, 0x32, 0x8c, 0x1b, 0x54, 0x95, 0x69,
+	0xc7, 0x0c, 0xc1, 0x82, 0xcd, 0x9a, 0xa2, 0x4f, 0xae, 0x68, 0x5f, 0xfa, 0x55, 0xb4, 0x0a, 0x60,
+	0xac, 0x2e, 0xe7, 0xe8, 0xcf, 0xa1, 0xf4, 0x96, 0x4c, 0x22, 0xd6, 0x8d, 0x2f, 0xc8, 0x44, 0x9c,
+	0x53, 0xc3, 0xfc, 0xf7, 0x82, 0x2e, 0x73, 0x08, 0xb5, 0xd7, 0x3c, 0x64, 0xc7, 0xde, 0xac, 0x38,
+	0x5c, 0x05, 0xf5, 0x34, 0x18, 0xfb, 0x8e, 0x2c, 0x3b, 0x82, 0xb8, 0x31, 0x06, 0xf5, 0x9f, 0x80,
+	0xb6, 0x37, 0xf6, 0xa8, 0xfb, 0x3a, 0x93, 0x09, 0x0f, 0xa0, 0x12, 0x72, 0x11, 0xb1, 0x89, 0x3c,
+	0xb9, 0x12, 0xc1, 0x38, 0x5e, 0xd5, 0xbf, 0x81, 0x15, 0xbe, 0x39, 0x93, 0xf1, 0x1b, 0x79, 0xe7,
+	0x34, 0xd9, 0xce, 0x74, 0x39, 0x76, 0xd1, 0xcd, 0x56, 0x3e, 0x83, 0x9a, 0x79, 0x83, 0x95, 0x99,
+	0x44, 0x2d, 0xe4, 0x13, 0x35, 0x36, 0xc6, 0x5c, 0x68, 0x8c, 0x79, 0xdd, 0x98, 0x17, 0xd0, 0xd8,
+	0x21, 0x1e, 0xa1, 0xe4, 0x26, 0xc1, 0x0e, 0x47, 0xc4, 0x0e, 0x8e, 0x49, 0xbd, 0x03, 0x1f, 0x73,
+	0xc1, 0xc9, 0x01, 0x42, 0xf6, 0xc3, 0x69, 0xd9, 0x1a, 0x93, 0x9d, 0x95, 0x92, 0x8a, 0xff, 0x56,
+	0x81, 0x7a, 0xcf, 0x1f, 0x84, 0xf3, 0xab, 0xcc, 0x2a, 0xa8, 0x0e, 0xf1, 0xa8, 0xcd, 0x85, 0x17,
+	0xb1, 0x20, 0x3e, 0xac, 0x66, 0xae, 0xb1, 0xa6, 0x16, 0x11, 0x31, 0xe9, 0x89, 0x56, 0x5c, 0xe5,
+	0x0c, 0x6b, 0x61, 0x85, 0xd9, 0x80, 0x86, 0x50, 0x4f, 0xda, 0x96, 0x9b, 0x75, 0x8a, 0x71, 0x38,
+	0xfd, 0x56, 0x81, 0xaa, 0x75, 0xe5, 0x77, 0xcf, 0xc9, 0xe0, 0x62, 0x86, 0x09, 0x5f, 0x40, 0xe9,
+	0xc2, 0x95, 0xf1, 0xd9, 0x14, 0xa3, 0x5d, 0x8c, 0xde, 0x7a, 0xeb, 0xfa, 0x0e, 0xe6, 0xcb, 0xd9,
+	0xf2, 0x55, 0xcc, 0x97, 0xaf, 0x47, 0x50, 0x62, 0x38, 0x54, 0x87, 0xca, 0x91, 0x81, 0xcd, 0xde,
+	0xc1, 0xbe, 0xb6, 0x84, 0x00, 0xca, 0xc6, 0x2f, 0x7a, 0xa6, 0x65, 0x6a, 0x0a, 0xfb, 0xdd, 0x79,
+	0x69, 0x1a, 0xfb, 0x96, 0x56, 0xd0, 0xff, 0xac, 0x80, 0x6a, 0x5d, 0xf9, 0x07, 0x23, 0xa4, 0x4b,
+	0xb9, 0x0a, 0x97, 0xdb, 0x94, 0x72, 0x0f, 0x46, 0x59, 0xa1, 0x52, 0xdb, 0xc2, 0x8c, 0x32, 0xf4,
+	0xdf, 0x16, 0x6f, 0x7d, 0x4d, 0xaa, 0x5d, 0x81, 0xa2, 0x69, 0x58, 0x42, 0xe5, 0x1d, 0x63, 0xd7,
+	0xb0, 0x0c, 0x4d, 0xd1, 0x87, 0x00, 0xd6, 0x95, 0x9f, 0x26, 0x50, 0x79, 0xc0, 0xfc, 0x11, 0x87,
+	0x4c, 0x23, 0xeb, 0x24, 0x2c, 0xd7, 0xd0, 0x1a, 0x14, 0x83, 0x11, 0x9b, 0x5f, 0x19, 0xa4, 0x96,
+	0xd8, 0x83, 0x19, 0x77, 0x41, 0x7b, 0xdf, 0x87, 0x3a, 0x17, 0x97, 0xce, 0xcb, 0x83, 0x60, 0x38,
+	0x64, 0x09, 0xe4, 0xc8, 0xc6, 0x97, 0x32, 0xd8, 0x84, 0x7a, 0x6a, 0xbb, 0x1e, 0x71, 0xfa, 0x5c,
+	0xb0, 0xec, 0x06, 0x75, 0xc1, 0xe3, 0x2a, 0xe9, 0x7f, 0x50, 0xa0, 0xbe, 0x1b, 0x0c, 0x2e, 0x62,
+	0x03, 0x66, 0xbd, 0x31, 0x56, 0x41, 0x0d, 0xde, 0xfb, 0x24, 0x2e, 0x86, 0x82, 0x60, 0x5c, 0x1a,
+	0x5c, 0x90, 0xf8, 0x92, 0x05, 0xf1, 0x7d, 0x76, 0xc5, 0x0d, 0x68, 0x08, 0xcd, 0xd2, 0x98, 0x15,
+	0x02, 0x95, 0x8c, 0x40, 0xfd, 0x08, 0x1a, 0xc7, 0xac, 0x5f, 0xcd, 0xcf, 0xbc, 0xb4, 0xed, 0x15,
+	0x72, 0x6d, 0xef, 0x66, 0x47, 0xff, 0x4e, 0x01, 0xd5, 0xb8, 0x24, 0x3e, 0x65, 0xa3, 0x0a, 0x9f,
+	0xef, 0x95, 0x74, 0x54, 0x31, 0x2e, 0xe5, 0x84, 0x8f, 0xf9, 0xd2, 0x87, 0x46, 0xdf, 0x77, 0xeb,
+	0x64, 0xff, 0x62, 0x1a, 0xf9, 0x34, 0x9c, 0xfc, 0xaf, 0xf7, 0x5c, 0xf4, 0x34, 0x7d, 0x31, 0xa8,
+	0x8b, 0x02, 0x21, 0x46, 0xea, 0xbf, 0x2e, 0x02, 0xc2, 0x64, 0xe4, 0xb9, 0x03, 0xbe, 0xb0, 0x47,
+	0xa2, 0xc8, 0x3e, 0x23, 0xe8, 0x71, 0x2e, 0xf9, 0xd7, 0x98, 0x61, 0xd7, 0x51, 0xd9, 0x4a, 0x70,
+	0x0f, 0x54, 0xc2, 0xae, 0xa7, 0x55, 0x98, 0x75, 0x5f, 0x62, 0x0d, 0xdd, 0x05, 0x95, 0x30, 0x57,
+	0x4a, 0x6f, 0xf0, 0x1c, 0xe4, 0xbe, 0xc5, 0x82, 0x8f, 0xb6, 0xa0, 0xc4, 0x1e, 0xf1, 0xb7, 0xb0,
+	0x9a, 0xe3, 0x6e, 0x0e, 0x65, 0xf4, 0x1c, 0x9a, 0x09, 0xd1, 0x77, 0xfd, 0xd3, 0xa0, 0x55, 0xe6,
+	0xe7, 0xce, 0x78, 0x1e, 0x2f, 0xfb, 0x59, 0x52, 0x8f, 0x64, 0xed, 0xa9, 0x81, 0x6a, 0xec, 0x5b,
+	0xf8, 0x9d, 0xb6, 0x84, 0x34, 0x68, 0x98, 0xfb, 0x9d, 0x43, 0xf3, 0xcd, 0x81, 0xd5, 0x37, 0xf6,
+	0x77, 0x34, 0x85, 0x2f, 0x1e, 0xf1, 0xaa, 0x89, 0x96, 0xa1, 0xf6, 0xc6, 0xe8, 0x60, 0xeb, 0xa5,
+	0xd1, 0xb1, 0xb4, 0x22, 0x5a, 0x05, 0x6d, 0xbf, 0xb3, 0x67, 0x98, 0x87, 0x9d, 0xae, 0xd1, 0xef,
+	0x62, 0xa3, 0x63, 0x19, 0x5a, 0x09, 0x21, 0x68, 0xa6, 0xdc, 0x1d, 0x7c, 0x70, 0xa8, 0xa9, 0xac,
+	0xa6, 0x99, 0xef, 0xf6, 0xbb, 0xc6, 0x8e, 0x56, 0xd6, 0xff, 0xa1, 0xc0, 0x4a, 0xc6, 0xc9, 0xfc,
+	0x45, 0xf5, 0x25, 0x94, 0xc2, 0xc0, 0x8b, 0xb3, 0xa0, 0x35, 0x75, 0x0f, 0x0c, 0xb2, 0x85, 0x03,
+	0x8f, 0x60, 0x8e, 0x62, 0x39, 0xe7, 0x11, 0xdb, 0x49, 0x6a, 0x86, 0xa4, 0x44, 0xbd, 0xf2, 0x7d,
+	0x32, 0xa0, 0xf2, 0x0d, 0x55, 0xc5, 0x29, 0x83, 0x15, 0x0f, 0xcf, 0x3e, 0xbb, 0x45, 0xf1, 0xf0,
+	0xec, 0x33, 0x7d, 0x1d, 0x4a, 0x4c, 0x20, 0x53, 0x7c, 0xd7, 0xe8, 0xec, 0x18, 0x58, 0x5b, 0x42,
+	0x0d, 0xa8, 0xbe, 0x3a, 0xd8, 0xdd, 0x3d, 0x38, 0x36, 0xb0, 0xa6, 0xe8, 0xdb, 0x50, 0xde, 0x23,
+	0xc3, 0x13, 0x12, 0xa2, 0x26, 0x14, 0x5c, 0x47, 0xe6, 0x4b, 0xc1, 0xe5, 0x2d, 0xca, 0x76, 0x9c,
+	0x90, 0x44, 0x91, 0xd4, 0x2f, 0x26, 0xf5, 0x9f, 0x03, 0x88, 0x3d, 0xfc, 0x93, 0xc6, 0x06, 0x54,
+	0x86, 0x9c, 0x8a, 0xeb, 0x39, 0x30, 0xbb, 0x05, 0x00, 0xc7, 0x4b, 0xf3, 0x8c, 0x65, 0x43, 0x41,
+	0x0d, 0xdb, 0xa7, 0x54, 0x24, 0xed, 0x2a, 0xa8, 0xae, 0xef, 0x90, 0xab, 0xb8, 0x7c, 0x71, 0x82,
+	0xd5, 0x5b, 0x4a, 0xc2, 0xa1, 0x7c, 0x79, 0xf0, 0xdf, 0xe8, 0xbe, 0x0c, 0xf9, 0x22, 0x77, 0x35,
+	0xe2, 0xae, 0x8e, 0x8f, 0xc9, 0x46, 0x3a, 0x82, 0x92, 0x63, 0x53, 0x9b, 0xfb, 0xab, 0x81, 0xf9,
+	0x6f, 0xfd, 0xa1, 0x8c, 0x97, 0x2a, 0x94, 0xf6, 0x0f, 0x0e, 0x0e, 0xb5, 0x25, 0xd6, 0x6c, 0xbb,
+	0x6f, 0x3a, 0xfb, 0xaf, 0x0d, 0xd6, 0x60, 0xeb, 0x50, 0xd9, 0x33, 0xf6, 0x5e, 0x1a, 0xd8, 0xd4,
+	0x0a, 0xfa, 0x6f, 0x14, 0xa8, 0x1f, 0x05, 0x94, 0x64, 0x6a, 0x3f, 0xd7, 0x45, 0xc9, 0xe8, 0xc2,
+	0x2e, 0xcc, 0xf6, 0x1d, 0xd7, 0xb1, 0x69, 0x32, 0xeb, 0x25, 0x0c, 0xb4, 0x01, 0x4d, 0xcf, 0x8e,
+	0x68, 0xdf, 0x0b, 0xce, 0xfa, 0xc2, 0x38, 0xd1, 0x0c, 0x1a, 0x8c, 0xbb, 0x1b, 0x9c, 0xf5, 0xb8,
+	0x8d, 0x3a, 0x2c, 0x27, 0x28, 0x2e, 0xa0, 0xc4, 0x41, 0x75, 0x09, 0xb2, 0x48, 0x38, 0xd4, 0x7f,
+	0x0a, 0x0d, 0xa1, 0x8a, 0x2c, 0xf6, 0xb3, 0x74, 0x69, 0x41, 0xe5, 0x2c, 0xb4, 0xfd, 0xcc, 0x04,
+	0x27, 0x49, 0xfd, 0x9f, 0x0a, 0x2c, 0x77, 0x46, 0x23, 0xe2, 0x3b, 0x37, 0xd9, 0x32, 0x2f, 0x28,
+	0x37, 0xa0, 0x39, 0x0a, 0xc9, 0xe5, 0x75, 0x2b, 0x18, 0x37, 0x6b, 0x45, 0x82, 0xca, 0x5a, 0x21,
+	0x41, 0xcc, 0x0a, 0x36, 0xae, 0xc6, 0x5f, 0xd3, 0xd4, 0x74, 0x5c, 0x4d, 0x2e, 0x0f, 0xc7, 0xab,
+	0xe8, 0x1e, 0x2c, 0x0b, 0xe1, 0x7d, 0xd1, 0xad, 0x5b, 0x65, 0xe9, 0x37, 0xce, 0xec, 0x72, 0x9e,
+	0xee, 0x40, 0x33, 0x36, 0xea, 0x66, 0xaf, 0x44, 0xe3, 0xc1, 0x20, 0x8e, 0xe5, 0x2a, 0x8e, 0xc9,
+	0xdb, 0xdd, 0x8e, 0xfe, 0x77, 0x05, 0x56, 0x4c, 0xdf, 0x1e, 0x45, 0xe7, 0x01, 0xfd, 0x2e, 0xde,
+	0xfb, 0x0c, 0x80, 0x4b, 0xc9, 0x4a, 0xa8, 0x31, 0x8e, 0x70, 0xdb, 0x1a, 0x70, 0x22, 0xeb, 0xb2,
+	0x2a, 0x63, 0x70, 0x7f, 0x65, 0xf2, 0x4b, 0x9d, 0x9f, 0x5f, 0x71, 0x9c, 0x97, 0x33, 0x71, 0x7e,
+	0x1f, 0xb4, 0x54, 0xe9, 0xf9, 0xde, 0x79, 0xf8, 0x23, 0xa8, 0x25, 0xdf, 0x15, 0xf8, 0x78, 0xb9,
+	0x7b, 0xdc, 0x79, 0x67, 0x6a, 0x4b, 0xac, 0x50, 0xf6, 0x5e, 0xf5, 0xe5, 0xb4, 0xa9, 0xa0, 0x26,
+	0x40, 0xef, 0x55, 0xff, 0x10, 0x1b, 0x9c, 0x2e, 0x3c, 0x7c, 0x0b, 0xb5, 0xa4, 0x69, 0xcc, 0x1c,
+	0xfc, 0xc4, 0xdc, 0x7a, 0xd8, 0xc3, 0x86, 0x56, 0x60, 0x00, 0xcb, 0xda, 0xd5, 0x8a, 0xa2, 0x12,
+	0xf7, 0xba, 0x96, 0x56, 0x62, 0x3f, 0xcd, 0xdd, 0xde, 0x8e, 0xa1, 0xa9, 0xdb, 0xff, 0xae, 0x41,
+	0xc5, 0xa4, 0x41, 0xc8, 0xfa, 0xd9, 0x3a, 0x14, 0x3b, 0x8e, 0x83, 0xf8, 0x60, 0x18, 0x7f, 0xc1,
+	0x6b, 0x8b, 0xfe, 0xc3, 0xbf, 0x10, 0x2f, 0xa1, 0x87, 0x00, 0x1d, 0xc7, 0x39, 0x76, 0xe9, 0x39,
+	0x9b, 0xeb, 0x57, 0xb2, 0x40, 0x8b, 0x7a, 0x79, 0xec, 0xa7, 0xa0, 0xf2, 0x05, 0x54, 0x91, 0xb0,
+	0xb6, 0x68, 0xfd, 0xfa, 0x12, 0xba, 0x07, 0x15, 0x56, 0xc3, 0x3a, 0x9e, 0x87, 0x96, 0x73, 0x6d,
+	0x26, 0x81, 0x3c, 0x51, 0xd0, 0x1d, 0x28, 0x63, 0x32, 0x0c, 0x2e, 0x33, 0x07, 0xe4, 0xce, 0x7f,
+	0x00, 0x35, 0xd6, 0xe9, 0x3a, 0x9e, 0x9b, 0x85, 0x70, 0x9d, 0x32, 0x5f, 0x99, 0xf4, 0x25, 0xf4,
+	0x05, 0x94, 0x4d, 0xf1, 0x10, 0x69, 0x26, 0x8b, 0x3c, 0x96, 0xf2, 0xe7, 0x6d, 0x42, 0xd1, 0x24,
+	0x14, 0x4d, 0x3d, 0x2c, 0xdb, 0x2b, 0x09, 0x9d, 0x1c, 0xf8, 0x14, 0x9a, 0xdd, 0x60, 0x38, 0xb2,
+	0x43, 0xd2, 0xf1, 0x1d, 0xf3, 0xbd, 0x3d, 0x12, 0x9b, 0xd2, 0x2f, 0x39, 0xed, 0x95, 0x84, 0x4e,
+	0x36, 0x3d, 0x82, 0x12, 0x7b, 0xec, 0x0b, 0xa7, 0x65, 0x3e, 0x94, 0xb4, 0xb5, 0x94, 0x91, 0x80,
+	0xbf, 0x84, 0x6a, 0xfc, 0x78, 0x46, 0x55, 0x69, 0x5a, 0xd4, 0x5e, 0xe5, 0x11, 0x38, 0xf5, 0xa8,
+	0xd6, 0x97, 0xd0, 0x33, 0x89, 0x66, 0xea, 0x7f, 0x9c, 0x60, 0x32, 0x36, 0xac, 0xe6, 0x99, 0xc9,
+	0xc6, 0x6d, 0xa8, 0x67, 0x5e, 0x97, 0x19, 0x49, 0xff, 0x97, 0x6c, 0xc8, 0x3f, 0x3c, 0x85, 0x1d,
+	0xec, 0xb9, 0x26, 0xec, 0xc8, 0xbc, 0x2b, 0xdb, 0x5a, 0xca, 0x48, 0xc0, 0x9b, 0xa0, 0xf2, 0x09,
+	0x18, 0xf1, 0xc5, 0xec, 0x30, 0x2c, 0x7d, 0xcf, 0xe2, 0x98, 0xdf, 0xf6, 0x67, 0xa0, 0x5a, 0xc1,
+	0x78, 0x70, 0x3e, 0xe7, 0xb2, 0x37, 0xa1, 0x2c, 0x3e, 0x54, 0x22, 0x3e, 0x97, 0xe4, 0x3e, 0x5a,
+	0xe6, 0x91, 0x77, 0xa1, 0x72, 0xc8, 0xde, 0x74, 0x11, 0x9d, 0x73, 0xd4, 0x67, 0x50, 0x64, 0xb1,
+	0x90, 0x2c, 0xd6, 0x65, 0x50, 0xf0, 0x99, 0x66, 0x09, 0x7d, 0x05, 0x2b, 0x5d, 0x3e, 0x63, 0xa6,
+	0x7f, 0x86, 0x5c, 0x1f, 0x85, 0xf2, 0x27, 0x3e, 0x81, 0x26, 0x0b, 0xe7, 0x04, 0x11, 0xa1, 0x74,
+	0xb9, 0x9d, 0xdf, 0xcc, 0x70, 0xdc, 0x89, 0xcb, 0x3b, 0x61, 0x30, 0x4a, 0x45, 0x4c, 0xa5, 0x41,
+	0xee, 0xf8, 0x47, 0xa0, 0xf2, 0x7f, 0x22, 0xa6, 0x41, 0xfc, 0xe4, 0xdc, 0x7f, 0x14, 0x22, 0x8a,
+	0xad, 0x2b, 0x1f, 0xc5, 0x2f, 0xd1, 0x5c, 0x40, 0x66, 0x5e, 0x67, 0xe2, 0x22, 0xd9, 0x1b, 0x46,
+	0x5c, 0x64, 0xe6, 0x9d, 0xd5, 0xd6, 0x52, 0x46, 0x26, 0x87, 0x54, 0x4c, 0x7c, 0xf2, 0xfe, 0x3a,
+	0x3a, 0xa7, 0xea, 0x7d, 0x28, 0x7f, 0xe3, 0x7b, 0x33, 0x4f, 0xcd, 0xe1, 0xb6, 0xa1, 0x16, 0x4f,
+	0x68, 0x24, 0xeb, 0xac, 0x4f, 0x66, 0xcf, 0xd0, 0x3c, 0x42, 0xbe, 0x86, 0x8f, 0x32, 0x2b, 0xcc,
+	0xee, 0x71, 0xce, 0xd1, 0x1f, 0xcf, 0x98, 0xfb, 0xf4, 0xa5, 0xed, 0xbf, 0x15, 0xa0, 0xd2, 0xf5,
+	0xc6, 0x11, 0x25, 0x21, 0x8b, 0x77, 0xa9, 0x0e, 0x6b, 0xe8, 0x42, 0xc7, 0xcc, 0x94, 0xd1, 0xd6,
+	0x52, 0x46, 0x62, 0xf9, 0xf3, 0xb8, 0x7d, 0xc7, 0xff, 0x3c, 0x71, 0xb7, 0xe7, 0x3a, 0x7a, 0x1b,
+	0x65, 0x59, 0xc9, 0xce, 0x9f, 0xc1, 0x4a, 0xcf, 0x8f, 0xa8, 0xed, 0x79, 0x71, 0x3b, 0x10, 0xd9,
+	0x39, 0xd5, 0xd1, 0xda, 0xab, 0x79, 0x66, 0xb2, 0x7f, 0x03, 0x6a, 0x1d, 0xc7, 0x91, 0x63, 0x62,
+	0xa6, 0xfb, 0x4c, 0x97, 0xc1, 0x86, 0x28, 0x93, 0x8b, 0x80, 0xf7, 0xa1, 0xb2, 0x27, 0xbb, 0x56,
+	0xc6, 0x6b, 0xcd, 0x14, 0x2e, 0x62, 0xf3, 0xa4, 0xcc, 0x87, 0xda, 0xa7, 0xff, 0x19, 0x00, 0xff,
+	0x2c, 0xd3, 0x05, 0x6b, 0x1c, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// StorageClient is the client API for Storage service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type StorageClient interface {
+	Add(ctx context.Context, in *KeyValue, opts ...grpc.CallOption) (*Empty, error)
+	AddWithTtl(ctx context.Context, in *KeyValueTtl, opts ...grpc.CallOption) (*Empty, error)
+	Value(ctx context.Context, in *Key, opts ...grpc.CallOption) (*T, error)
+	ListAll(ctx context.Context, in *Namespace, opts ...grpc.CallOption) (Storage_ListAllClient, error)
+	Remove(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Empty, error)
+	TimeAlive(ctx context.Context, in *Key, opts ...grpc.CallOption) (*TtlResponse, error)
+	SetTtl(ctx context.Context, in *TtlRequest, opts ...grpc.CallOption) (*Empty, error)
+	Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*SetResponse, error)
+	CompareAndSwap(ctx context.Context, in *CasRequest, opts ...grpc.CallOption) (*CasResponse, error)
+	Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*ScanResponse, error)
+	MultiGet(ctx context.Context, in *Keys, opts ...grpc.CallOption) (*MultiGetResponse, error)
+	MultiSet(ctx context.Context, in *MultiSetRequest, opts ...grpc.CallOption) (*MultiSetResponse, error)
+	MultiDelete(ctx context.Context, in *Keys, opts ...grpc.CallOption) (*MultiDeleteResponse, error)
+	// Incr adds delta to the integer value of the key, creating it with delta and the ttl if it is absent.
+	// The ttl of an existing key is kept even if the ttl is given, unless reset_ttl is set,
+	// so counters of fixed windows can pass the same ttl on every call.
+	Incr(ctx context.Context, in *IncrRequest, opts ...grpc.CallOption) (*IncrResponse, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Storage_WatchClient, error)
+	Touch(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Empty, error)
+	Expire(ctx context.Context, in *ExpireRequest, opts ...grpc.CallOption) (*Empty, error)
+	Persist(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Empty, error)
+	Ttl(ctx context.Context, in *Key, opts ...grpc.CallOption) (*TtlInfo, error)
+	CreateNamespace(ctx context.Context, in *NamespaceInfo, opts ...grpc.CallOption) (*Empty, error)
+	ListNamespaces(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*NamespaceList, error)
+	DropNamespace(ctx context.Context, in *Namespace, opts ...grpc.CallOption) (*Empty, error)
+	// Stats returns the counters of the namespace since the server started.
+	Stats(ctx context.Context, in *Namespace, opts ...grpc.CallOption) (*StatsResponse, error)
+	Txn(ctx context.Context, in *TxnRequest, opts ...grpc.CallOption) (*TxnResponse, error)
+	Lock(ctx context.Context, in *LockRequest, opts ...grpc.CallOption) (*LockResponse, error)
+	Renew(ctx context.Context, in *LockRequest, opts ...grpc.CallOption) (*Empty, error)
+	Unlock(ctx context.Context, in *LockRequest, opts ...grpc.CallOption) (*Empty, error)
+	Replicate(ctx context.Context, in *Empty, opts ...grpc.CallOption) (Storage_ReplicateClient, error)
+	ReplicationStatus(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ReplicationInfo, error)
+}
+
+type storageClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewStorageClient(cc *grpc.ClientConn) StorageClient {
+	return &storageClient{cc}
+}
+
+func (c *storageClient) Add(ctx context.Context, in *KeyValue, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/pb.Storage/Add", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) AddWithTtl(ctx context.Context, in *KeyValueTtl, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/pb.Storage/AddWithTtl", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Value(ctx context.Context, in *Key, opts ...grpc.CallOption) (*T, error) {
+	out := new(T)
+	err := c.cc.Invoke(ctx, "/pb.Storage/Value", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) ListAll(ctx context.Context, in *Namespace, opts ...grpc.CallOption) (Storage_ListAllClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[0], "/pb.Storage/ListAll", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &storageListAllClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Storage_ListAllClient interface {
+	Recv() (*T, error)
+	grpc.ClientStream
+}
+
+type storageListAllClient struct {
+	grpc.ClientStream
+}
+
+func (x *storageListAllClient) Recv() (*T, error) {
+	m := new(T)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *storageClient) Remove(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/pb.Storage/Remove", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) TimeAlive(ctx context.Context, in *Key, opts ...grpc.CallOption) (*TtlResponse, error) {
+	out := new(TtlResponse)
+	err := c.cc.Invoke(ctx, "/pb.Storage/TimeAlive", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) SetTtl(ctx context.Context, in *TtlRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/pb.Storage/SetTtl", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*SetResponse, error) {
+	out := new(SetResponse)
+	err := c.cc.Invoke(ctx, "/pb.Storage/Set", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) CompareAndSwap(ctx context.Context, in *CasRequest, opts ...grpc.CallOption) (*CasResponse, error) {
+	out := new(CasResponse)
+	err := c.cc.Invoke(ctx, "/pb.Storage/CompareAndSwap", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (*ScanResponse, error) {
+	out := new(ScanResponse)
+	err := c.cc.Invoke(ctx, "/pb.Storage/Scan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) MultiGet(ctx context.Context, in *Keys, opts ...grpc.CallOption) (*MultiGetResponse, error) {
+	out := new(MultiGetResponse)
+	err := c.cc.Invoke(ctx, "/pb.Storage/MultiGet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) MultiSet(ctx context.Context, in *MultiSetRequest, opts ...grpc.CallOption) (*MultiSetResponse, error) {
+	out := new(MultiSetResponse)
+	err := c.cc.Invoke(ctx, "/pb.Storage/MultiSet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) MultiDelete(ctx context.Context, in *Keys, opts ...grpc.CallOption) (*MultiDeleteResponse, error) {
+	out := new(MultiDeleteResponse)
+	err := c.cc.Invoke(ctx, "/pb.Storage/MultiDelete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Incr(ctx context.Context, in *IncrRequest, opts ...grpc.CallOption) (*IncrResponse, error) {
+	out := new(IncrResponse)
+	err := c.cc.Invoke(ctx, "/pb.Storage/Incr", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Storage_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[1], "/pb.Storage/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &storageWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Storage_WatchClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type storageWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *storageWatchClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *storageClient) Touch(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/pb.Storage/Touch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Expire(ctx context.Context, in *ExpireRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/pb.Storage/Expire", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Persist(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/pb.Storage/Persist", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Ttl(ctx context.Context, in *Key, opts ...grpc.CallOption) (*TtlInfo, error) {
+	out := new(TtlInfo)
+	err := c.cc.Invoke(ctx, "/pb.Storage/Ttl", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) CreateNamespace(ctx context.Context, in *NamespaceInfo, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/pb.Storage/CreateNamespace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) ListNamespaces(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*NamespaceList, error) {
+	out := new(NamespaceList)
+	err := c.cc.Invoke(ctx, "/pb.Storage/ListNamespaces", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) DropNamespace(ctx context.Context, in *Namespace, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/pb.Storage/DropNamespace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Stats(ctx context.Context, in *Namespace, opts ...grpc.CallOption) (*StatsResponse, error) {
+	out := new(StatsResponse)
+	err := c.cc.Invoke(ctx, "/pb.Storage/Stats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Txn(ctx context.Context, in *TxnRequest, opts ...grpc.CallOption) (*TxnResponse, error) {
+	out := new(TxnResponse)
+	err := c.cc.Invoke(ctx, "/pb.Storage/Txn", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Lock(ctx context.Context, in *LockRequest, opts ...grpc.CallOption) (*LockResponse, error) {
+	out := new(LockResponse)
+	err := c.cc.Invoke(ctx, "/pb.Storage/Lock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Renew(ctx context.Context, in *LockRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/pb.Storage/Renew", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Unlock(ctx context.Context, in *LockRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/pb.Storage/Unlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageClient) Replicate(ctx context.Context, in *Empty, opts ...grpc.CallOption) (Storage_ReplicateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[2], "/pb.Storage/Replicate", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &storageReplicateClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Storage_ReplicateClient interface {
+	Recv() (*ReplicationMessage, error)
+	grpc.ClientStream
+}
+
+type storageReplicateClient struct {
+	grpc.ClientStream
+}
+
+func (x *storageReplicateClient) Recv() (*ReplicationMessage, error) {
+	m := new(ReplicationMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *storageClient) ReplicationStatus(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ReplicationInfo, error) {
+	out := new(ReplicationInfo)
+	err := c.cc.Invoke(ctx, "/pb.Storage/ReplicationStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StorageServer is the server API for Storage service.
+type StorageServer interface {
+	Add(context.Context, *KeyValue) (*Empty, error)
+	AddWithTtl(context.Context, *KeyValueTtl) (*Empty, error)
+	Value(context.Context, *Key) (*T, error)
+	ListAll(*Namespace, Storage_ListAllServer) error
+	Remove(context.Context, *Key) (*Empty, error)
+	TimeAlive(context.Context, *Key) (*TtlResponse, error)
+	SetTtl(context.Context, *TtlRequest) (*Empty, error)
+	Set(context.Context, *SetRequest) (*SetResponse, error)
+	CompareAndSwap(context.Context, *CasRequest) (*CasResponse, error)
+	Scan(context.Context, *ScanRequest) (*ScanResponse, error)
+	MultiGet(context.Context, *Keys) (*MultiGetResponse, error)
+	MultiSet(context.Context, *MultiSetRequest) (*MultiSetResponse, error)
+	MultiDelete(context.Context, *Keys) (*MultiDeleteResponse, error)
+	// Incr adds delta to the integer value of the key, creating it with delta and the ttl if it is absent.
+	// The ttl of an existing key is kept even if the ttl is given, unless reset_ttl is set,
+	// so counters of fixed windows can pass the same ttl on every call.
+	Incr(context.Context, *IncrRequest) (*IncrResponse, error)
+	Watch(*WatchRequest, Storage_WatchServer) error
+	Touch(context.Context, *Key) (*Empty, error)
+	Expire(context.Context, *ExpireRequest) (*Empty, error)
+	Persist(context.Context, *Key) (*Empty, error)
+	Ttl(context.Context, *Key) (*TtlInfo, error)
+	CreateNamespace(context.Context, *NamespaceInfo) (*Empty, error)
+	ListNamespaces(context.Context, *Empty) (*NamespaceList, error)
+	DropNamespace(context.Context, *Namespace) (*Empty, error)
+	// Stats returns the counters of the namespace since the server started.
+	Stats(context.Context, *Namespace) (*StatsResponse, error)
+	Txn(context.Context, *TxnRequest) (*TxnResponse, error)
+	Lock(context.Context, *LockRequest) (*LockResponse, error)
+	Renew(context.Context, *LockRequest) (*Empty, error)
+	Unlock(context.Context, *LockRequest) (*Empty, error)
+	Replicate(*Empty, Storage_ReplicateServer) error
+	ReplicationStatus(context.Context, *Empty) (*ReplicationInfo, error)
+}
+
+// UnimplementedStorageServer can be embedded to have forward compatible implementations.
+type UnimplementedStorageServer struct {
+}
+
+func (*UnimplementedStorageServer) Add(ctx context.Context, req *KeyValue) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Add not implemented")
+}
+func (*UnimplementedStorageServer) AddWithTtl(ctx context.Context, req *KeyValueTtl) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddWithTtl not implemented")
+}
+func (*UnimplementedStorageServer) Value(ctx context.Context, req *Key) (*T, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Value not implemented")
+}
+func (*UnimplementedStorageServer) ListAll(req *Namespace, srv Storage_ListAllServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListAll not implemented")
+}
+func (*UnimplementedStorageServer) Remove(ctx context.Context, req *Key) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Remove not implemented")
+}
+func (*UnimplementedStorageServer) TimeAlive(ctx context.Context, req *Key) (*TtlResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TimeAlive not implemented")
+}
+func (*UnimplementedStorageServer) SetTtl(ctx context.Context, req *TtlRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTtl not implemented")
+}
+func (*UnimplementedStorageServer) Set(ctx context.Context, req *SetRequest) (*SetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Set not implemented")
+}
+func (*UnimplementedStorageServer) CompareAndSwap(ctx context.Context, req *CasRequest) (*CasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompareAndSwap not implemented")
+}
+func (*UnimplementedStorageServer) Scan(ctx context.Context, req *ScanRequest) (*ScanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Scan not implemented")
+}
+func (*UnimplementedStorageServer) MultiGet(ctx context.Context, req *Keys) (*MultiGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MultiGet not implemented")
+}
+func (*UnimplementedStorageServer) MultiSet(ctx context.Context, req *MultiSetRequest) (*MultiSetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MultiSet not implemented")
+}
+func (*UnimplementedStorageServer) MultiDelete(ctx context.Context, req *Keys) (*MultiDeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MultiDelete not implemented")
+}
+func (*UnimplementedStorageServer) Incr(ctx context.Context, req *IncrRequest) (*IncrResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Incr not implemented")
+}
+func (*UnimplementedStorageServer) Watch(req *WatchRequest, srv Storage_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (*UnimplementedStorageServer) Touch(ctx context.Context, req *Key) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Touch not implemented")
+}
+func (*UnimplementedStorageServer) Expire(ctx context.Context, req *ExpireRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Expire not implemented")
+}
+func (*UnimplementedStorageServer) Persist(ctx context.Context, req *Key) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Persist not implemented")
+}
+func (*UnimplementedStorageServer) Ttl(ctx context.Context, req *Key) (*TtlInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ttl not implemented")
+}
+func (*UnimplementedStorageServer) CreateNamespace(ctx context.Context, req *NamespaceInfo) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateNamespace not implemented")
+}
+func (*UnimplementedStorageServer) ListNamespaces(ctx context.Context, req *Empty) (*NamespaceList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNamespaces not implemented")
+}
+func (*UnimplementedStorageServer) DropNamespace(ctx context.Context, req *Namespace) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DropNamespace not implemented")
+}
+func (*UnimplementedStorageServer) Stats(ctx context.Context, req *Namespace) (*StatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stats not implemented")
+}
+func (*UnimplementedStorageServer) Txn(ctx context.Context, req *TxnRequest) (*TxnResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Txn not implemented")
+}
+func (*UnimplementedStorageServer) Lock(ctx context.Context, req *LockRequest) (*LockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Lock not implemented")
+}
+func (*UnimplementedStorageServer) Renew(ctx context.Context, req *LockRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Renew not implemented")
+}
+func (*UnimplementedStorageServer) Unlock(ctx context.Context, req *LockRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unlock not implemented")
+}
+func (*UnimplementedStorageServer) Replicate(req *Empty, srv Storage_ReplicateServer) error {
+	return status.Errorf(codes.Unimplemented, "method Replicate not implemented")
+}
+func (*UnimplementedStorageServer) ReplicationStatus(ctx context.Context, req *Empty) (*ReplicationInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplicationStatus not implemented")
+}
+
+func RegisterStorageServer(s *grpc.Server, srv StorageServer) {
+	s.RegisterService(&_Storage_serviceDesc, srv)
+}
+
+func _Storage_Add_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeyValue)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Add(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/Add",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Add(ctx, req.(*KeyValue))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_AddWithTtl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeyValueTtl)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).AddWithTtl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/AddWithTtl",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).AddWithTtl(ctx, req.(*KeyValueTtl))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Value_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Value(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/Value",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Value(ctx, req.(*Key))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_ListAll_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Namespace)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageServer).ListAll(m, &storageListAllServer{stream})
+}
+
+type Storage_ListAllServer interface {
+	Send(*T) error
+	grpc.ServerStream
+}
+
+type storageListAllServer struct {
+	grpc.ServerStream
+}
+
+func (x *storageListAllServer) Send(m *T) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Storage_Remove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Remove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/Remove",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Remove(ctx, req.(*Key))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_TimeAlive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).TimeAlive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/TimeAlive",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).TimeAlive(ctx, req.(*Key))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_SetTtl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TtlRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).SetTtl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/SetTtl",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).SetTtl(ctx, req.(*TtlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Set_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Set(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/Set",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Set(ctx, req.(*SetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_CompareAndSwap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).CompareAndSwap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/CompareAndSwap",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).CompareAndSwap(ctx, req.(*CasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Scan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Scan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/Scan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Scan(ctx, req.(*ScanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-var fileDescriptor_5fca3b110c9bbf3a = []byte{
-	// 365 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x91, 0xc1, 0x4f, 0xe2, 0x40,
-	0x14, 0xc6, 0x29, 0x4d, 0xe9, 0xf2, 0xba, 0xd9, 0xdd, 0x4c, 0x36, 0x11, 0x6a, 0x02, 0x64, 0x12,
-	0x23, 0xd1, 0xa4, 0x10, 0x3c, 0xe9, 0xad, 0x89, 0x9e, 0xf0, 0x60, 0x4a, 0xa3, 0xe7, 0x96, 0x8e,
-	0xd0, 0x38, 0x65, 0x2a, 0x7d, 0x25, 0xe9, 0xd9, 0x7f, 0xdc, 0x4c, 0xa7, 0xc5, 0x22, 0x1e, 0xf4,
-	0x36, 0x33, 0xdf, 0xf7, 0xbd, 0x79, 0xef, 0xf7, 0xc0, 0x5a, 0x06, 0xcb, 0x35, 0x73, 0xd2, 0xad,
-	0x40, 0x41, 0xda, 0x69, 0x68, 0x0f, 0x56, 0x42, 0xac, 0x38, 0x9b, 0x94, 0x2f, 0x61, 0xfe, 0x3c,
-	0x89, 0xf2, 0x6d, 0x80, 0xb1, 0xd8, 0x28, 0x8f, 0x3d, 0xfc, 0xac, 0x63, 0x9c, 0xb0, 0x0c, 0x83,
-	0x24, 0x55, 0x06, 0x6a, 0x82, 0x71, 0x97, 0xa4, 0x58, 0xd0, 0x13, 0xd0, 0xe7, 0xac, 0x20, 0xff,
-	0x40, 0x7f, 0x61, 0x45, 0x4f, 0x1b, 0x69, 0xe3, 0xae, 0x27, 0x8f, 0xb4, 0x0f, 0x9a, 0x4f, 0xfe,
-	0x83, 0xb1, 0x0b, 0x78, 0xce, 0x2a, 0x41, 0x5d, 0xe8, 0x35, 0xfc, 0x9a, 0xb3, 0xe2, 0x51, 0x9e,
-	0x8f, 0x83, 0xe4, 0xb4, 0xce, 0xb4, 0x47, 0xda, 0xd8, 0x9a, 0x19, 0x4e, 0x1a, 0x3a, 0x7e, 0x1d,
-	0x8d, 0xc1, 0xaa, 0xa3, 0x3e, 0xf2, 0x1f, 0xa6, 0xc9, 0x25, 0xe8, 0x88, 0xbc, 0xa7, 0x97, 0x52,
-	0xdf, 0x51, 0x43, 0x3a, 0xf5, 0x90, 0xce, 0x6d, 0x05, 0xc1, 0x93, 0x2e, 0xfa, 0x00, 0xe0, 0x23,
-	0xf7, 0xd8, 0x6b, 0xce, 0x32, 0xfc, 0xe2, 0xa7, 0x29, 0x18, 0x25, 0x91, 0xea, 0x27, 0xfb, 0xa8,
-	0x9c, 0x5f, 0x33, 0xf3, 0x94, 0x91, 0xde, 0x80, 0x55, 0x56, 0xcc, 0x52, 0xb1, 0xc9, 0xf6, 0xdd,
-	0x68, 0xdf, 0xe9, 0x66, 0xf6, 0xd6, 0x06, 0x73, 0x81, 0x62, 0x1b, 0xac, 0x18, 0x19, 0x81, 0xee,
-	0x46, 0x11, 0xf9, 0x2d, 0x67, 0xab, 0x69, 0xd8, 0x5d, 0x79, 0x53, 0x3b, 0x69, 0x91, 0x0b, 0x00,
-	0x37, 0x8a, 0x9e, 0x62, 0x5c, 0x4b, 0x4a, 0x7f, 0x9b, 0x46, 0x1f, 0xf9, 0xa1, 0xb7, 0x0f, 0x86,
-	0x5a, 0x85, 0x59, 0xd9, 0x6c, 0x05, 0x8d, 0xb6, 0xc8, 0x10, 0xcc, 0xfb, 0x38, 0x43, 0x97, 0x73,
-	0xf2, 0x11, 0xd9, 0xcb, 0x53, 0x8d, 0x0c, 0xa0, 0xe3, 0xb1, 0x44, 0xec, 0x1a, 0xe1, 0x83, 0xda,
-	0xe7, 0xd0, 0x95, 0x14, 0x5c, 0x1e, 0x37, 0x2d, 0x65, 0x3f, 0x0d, 0x12, 0xb4, 0x45, 0xce, 0xa0,
-	0xb3, 0x60, 0x28, 0x9b, 0xfd, 0xb3, 0x17, 0x4b, 0xf0, 0x07, 0xf5, 0xc2, 0x4e, 0x49, 0xe7, 0xea,
-	0x3d, 0x00, 0x00, 0xff, 0xff, 0xb0, 0x68, 0x2a, 0x4f, 0xd1, 0x02, 0x00, 0x00,
+func _Storage_MultiGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Keys)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).MultiGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/MultiGet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).MultiGet(ctx, req.(*Keys))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_MultiSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MultiSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).MultiSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/MultiSet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).MultiSet(ctx, req.(*MultiSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_MultiDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Keys)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).MultiDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/MultiDelete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).MultiDelete(ctx, req.(*Keys))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Incr_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncrRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Incr(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/Incr",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Incr(ctx, req.(*IncrRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageServer).Watch(m, &storageWatchServer{stream})
+}
+
+type Storage_WatchServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type storageWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *storageWatchServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Storage_Touch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Touch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/Touch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Touch(ctx, req.(*Key))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Expire_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExpireRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Expire(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/Expire",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Expire(ctx, req.(*ExpireRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Persist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Persist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/Persist",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Persist(ctx, req.(*Key))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Ttl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Key)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Ttl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/Ttl",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Ttl(ctx, req.(*Key))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_CreateNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NamespaceInfo)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).CreateNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/CreateNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).CreateNamespace(ctx, req.(*NamespaceInfo))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_ListNamespaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).ListNamespaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/ListNamespaces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).ListNamespaces(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_DropNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Namespace)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).DropNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/DropNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).DropNamespace(ctx, req.(*Namespace))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Namespace)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Stats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/Stats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Stats(ctx, req.(*Namespace))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Txn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TxnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Txn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/Txn",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Txn(ctx, req.(*TxnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Lock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Lock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/Lock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Lock(ctx, req.(*LockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Renew_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Renew(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/Renew",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Renew(ctx, req.(*LockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Unlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).Unlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/Unlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).Unlock(ctx, req.(*LockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Storage_Replicate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageServer).Replicate(m, &storageReplicateServer{stream})
+}
+
+type Storage_ReplicateServer interface {
+	Send(*ReplicationMessage) error
+	grpc.ServerStream
+}
+
+type storageReplicateServer struct {
+	grpc.ServerStream
+}
+
+func (x *storageReplicateServer) Send(m *ReplicationMessage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Storage_ReplicationStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageServer).ReplicationStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Storage/ReplicationStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageServer).ReplicationStatus(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+var _Storage_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.Storage",
+	HandlerType: (*StorageServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Add",
+			Handler:    _Storage_Add_Handler,
+		},
+		{
+			MethodName: "AddWithTtl",
+			Handler:    _Storage_AddWithTtl_Handler,
+		},
+		{
+			MethodName: "Value",
+			Handler:    _Storage_Value_Handler,
+		},
+		{
+			MethodName: "Remove",
+			Handler:    _Storage_Remove_Handler,
+		},
+		{
+			MethodName: "TimeAlive",
+			Handler:    _Storage_TimeAlive_Handler,
+		},
+		{
+			MethodName: "SetTtl",
+			Handler:    _Storage_SetTtl_Handler,
+		},
+		{
+			MethodName: "Set",
+			Handler:    _Storage_Set_Handler,
+		},
+		{
+			MethodName: "CompareAndSwap",
+			Handler:    _Storage_CompareAndSwap_Handler,
+		},
+		{
+			MethodName: "Scan",
+			Handler:    _Storage_Scan_Handler,
+		},
+		{
+			MethodName: "MultiGet",
+			Handler:    _Storage_MultiGet_Handler,
+		},
+		{
+			MethodName: "MultiSet",
+			Handler:    _Storage_MultiSet_Handler,
+		},
+		{
+			MethodName: "MultiDelete",
+			Handler:    _Storage_MultiDelete_Handler,
+		},
+		{
+			MethodName: "Incr",
+			Handler:    _Storage_Incr_Handler,
+		},
+		{
+			MethodName: "Touch",
+			Handler:    _Storage_Touch_Handler,
+		},
+		{
+			MethodName: "Expire",
+			Handler:    _Storage_Expire_Handler,
+		},
+		{
+			MethodName: "Persist",
+			Handler:    _Storage_Persist_Handler,
+		},
+		{
+			MethodName: "Ttl",
+			Handler:    _Storage_Ttl_Handler,
+		},
+		{
+			MethodName: "CreateNamespace",
+			Handler:    _Storage_CreateNamespace_Handler,
+		},
+		{
+			MethodName: "ListNamespaces",
+			Handler:    _Storage_ListNamespaces_Handler,
+		},
+		{
+			MethodName: "DropNamespace",
+			Handler:    _Storage_DropNamespace_Handler,
+		},
+		{
+			MethodName: "Stats",
+			Handler:    _Storage_Stats_Handler,
+		},
+		{
+			MethodName: "Txn",
+			Handler:    _Storage_Txn_Handler,
+		},
+		{
+			MethodName: "Lock",
+			Handler:    _Storage_Lock_Handler,
+		},
+		{
+			MethodName: "Renew",
+			Handler:    _Storage_Renew_Handler,
+		},
+		{
+			MethodName: "Unlock",
+			Handler:    _Storage_Unlock_Handler,
+		},
+		{
+			MethodName: "ReplicationStatus",
+			Handler:    _Storage_ReplicationStatus_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListAll",
+			Handler:       _Storage_ListAll_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _Storage_Watch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Replicate",
+			Handler:       _Storage_Replicate_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "cache.proto",
+}
 
-// StorageClient is the client API for Storage service.
+// ClusterClient is the client API for Cluster service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type StorageClient interface {
-	Add(ctx context.Context, in *KeyValue, opts ...grpc.CallOption) (*Empty, error)
-	AddWithTtl(ctx context.Context, in *KeyValueTtl, opts ...grpc.CallOption) (*Empty, error)
-	Value(ctx context.Context, in *Key, opts ...grpc.CallOption) (*T, error)
-	ListAll(ctx context.Context, in *Empty, opts ...grpc.CallOption) (Storage_ListAllClient, error)
-	Remove(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Empty, error)
-	TimeAlive(ctx context.Context, in *Key, opts ...grpc.CallOption) (*TtlResponse, error)
-	SetTtl(ctx context.Context, in *TtlRequest, opts ...grpc.CallOption) (*Empty, error)
+type ClusterClient interface {
+	RequestVote(ctx context.Context, in *VoteRequest, opts ...grpc.CallOption) (*VoteResponse, error)
+	AppendEntries(ctx context.Context, in *AppendRequest, opts ...grpc.CallOption) (*AppendResponse, error)
+	InstallSnapshot(ctx context.Context, in *SnapshotRequest, opts ...grpc.CallOption) (*SnapshotResponse, error)
+	AddMember(ctx context.Context, in *Member, opts ...grpc.CallOption) (*Empty, error)
+	RemoveMember(ctx context.Context, in *Member, opts ...grpc.CallOption) (*Empty, error)
+	Members(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*MemberList, error)
 }
 
-type storageClient struct {
+type clusterClient struct {
 	cc *grpc.ClientConn
 }
 
-func NewStorageClient(cc *grpc.ClientConn) StorageClient {
-	return &storageClient{cc}
+func NewClusterClient(cc *grpc.ClientConn) ClusterClient {
+	return &clusterClient{cc}
 }
 
-func (c *storageClient) Add(ctx context.Context, in *KeyValue, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
-	err := c.cc.Invoke(ctx, "/pb.Storage/Add", in, out, opts...)
+func (c *clusterClient) RequestVote(ctx context.Context, in *VoteRequest, opts ...grpc.CallOption) (*VoteResponse, error) {
+	out := new(VoteResponse)
+	err := c.cc.Invoke(ctx, "/pb.Cluster/RequestVote", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *storageClient) AddWithTtl(ctx context.Context, in *KeyValueTtl, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
-	err := c.cc.Invoke(ctx, "/pb.Storage/AddWithTtl", in, out, opts...)
+func (c *clusterClient) AppendEntries(ctx context.Context, in *AppendRequest, opts ...grpc.CallOption) (*AppendResponse, error) {
+	out := new(AppendResponse)
+	err := c.cc.Invoke(ctx, "/pb.Cluster/AppendEntries", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *storageClient) Value(ctx context.Context, in *Key, opts ...grpc.CallOption) (*T, error) {
-	out := new(T)
-	err := c.cc.Invoke(ctx, "/pb.Storage/Value", in, out, opts...)
+func (c *clusterClient) InstallSnapshot(ctx context.Context, in *SnapshotRequest, opts ...grpc.CallOption) (*SnapshotResponse, error) {
+	out := new(SnapshotResponse)
+	err := c.cc.Invoke(ctx, "/pb.Cluster/InstallSnapshot", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *storageClient) ListAll(ctx context.Context, in *Empty, opts ...grpc.CallOption) (Storage_ListAllClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[0], "/pb.Storage/ListAll", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &storageListAllClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
-	}
-	return x, nil
-}
-
-type Storage_ListAllClient interface {
-	Recv() (*T, error)
-	grpc.ClientStream
-}
-
-type storageListAllClient struct {
-	grpc.ClientStream
-}
-
-func (x *storageListAllClient) Recv() (*T, error) {
-	m := new(T)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
-}
-
-func (c *storageClient) Remove(ctx context.Context, in *Key, opts ...grpc.CallOption) (*Empty, error) {
+func (c *clusterClient) AddMember(ctx context.Context, in *Member, opts ...grpc.CallOption) (*Empty, error) {
 	out := new(Empty)
-	err := c.cc.Invoke(ctx, "/pb.Storage/Remove", in, out, opts...)
+	err := c.cc.Invoke(ctx, "/pb.Cluster/AddMember", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *storageClient) TimeAlive(ctx context.Context, in *Key, opts ...grpc.CallOption) (*TtlResponse, error) {
-	out := new(TtlResponse)
-	err := c.cc.Invoke(ctx, "/pb.Storage/TimeAlive", in, out, opts...)
+func (c *clusterClient) RemoveMember(ctx context.Context, in *Member, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/pb.Cluster/RemoveMember", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *storageClient) SetTtl(ctx context.Context, in *TtlRequest, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
-	err := c.cc.Invoke(ctx, "/pb.Storage/SetTtl", in, out, opts...)
+func (c *clusterClient) Members(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*MemberList, error) {
+	out := new(MemberList)
+	err := c.cc.Invoke(ctx, "/pb.Cluster/Members", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// StorageServer is the server API for Storage service.
-type StorageServer interface {
-	Add(context.Context, *KeyValue) (*Empty, error)
-	AddWithTtl(context.Context, *KeyValueTtl) (*Empty, error)
-	Value(context.Context, *Key) (*T, error)
-	ListAll(*Empty, Storage_ListAllServer) error
-	Remove(context.Context, *Key) (*Empty, error)
-	TimeAlive(context.Context, *Key) (*TtlResponse, error)
-	SetTtl(context.Context, *TtlRequest) (*Empty, error)
+// ClusterServer is the server API for Cluster service.
+type ClusterServer interface {
+	RequestVote(context.Context, *VoteRequest) (*VoteResponse, error)
+	AppendEntries(context.Context, *AppendRequest) (*AppendResponse, error)
+	InstallSnapshot(context.Context, *SnapshotRequest) (*SnapshotResponse, error)
+	AddMember(context.Context, *Member) (*Empty, error)
+	RemoveMember(context.Context, *Member) (*Empty, error)
+	Members(context.Context, *Empty) (*MemberList, error)
 }
 
-// UnimplementedStorageServer can be embedded to have forward compatible implementations.
-type UnimplementedStorageServer struct {
+// UnimplementedClusterServer can be embedded to have forward compatible implementations.
+type UnimplementedClusterServer struct {
 }
 
-func (*UnimplementedStorageServer) Add(ctx context.Context, req *KeyValue) (*Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Add not implemented")
-}
-func (*UnimplementedStorageServer) AddWithTtl(ctx context.Context, req *KeyValueTtl) (*Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method AddWithTtl not implemented")
+func (*UnimplementedClusterServer) RequestVote(ctx context.Context, req *VoteRequest) (*VoteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestVote not implemented")
 }
-func (*UnimplementedStorageServer) Value(ctx context.Context, req *Key) (*T, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Value not implemented")
+func (*UnimplementedClusterServer) AppendEntries(ctx context.Context, req *AppendRequest) (*AppendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AppendEntries not implemented")
 }
-func (*UnimplementedStorageServer) ListAll(req *Empty, srv Storage_ListAllServer) error {
-	return status.Errorf(codes.Unimplemented, "method ListAll not implemented")
+func (*UnimplementedClusterServer) InstallSnapshot(ctx context.Context, req *SnapshotRequest) (*SnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InstallSnapshot not implemented")
 }
-func (*UnimplementedStorageServer) Remove(ctx context.Context, req *Key) (*Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Remove not implemented")
+func (*UnimplementedClusterServer) AddMember(ctx context.Context, req *Member) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddMember not implemented")
 }
-func (*UnimplementedStorageServer) TimeAlive(ctx context.Context, req *Key) (*TtlResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method TimeAlive not implemented")
+func (*UnimplementedClusterServer) RemoveMember(ctx context.Context, req *Member) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveMember not implemented")
 }
-func (*UnimplementedStorageServer) SetTtl(ctx context.Context, req *TtlRequest) (*Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SetTtl not implemented")
+func (*UnimplementedClusterServer) Members(ctx context.Context, req *Empty) (*MemberList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Members not implemented")
 }
 
-func RegisterStorageServer(s *grpc.Server, srv StorageServer) {
-	s.RegisterService(&_Storage_serviceDesc, srv)
+func RegisterClusterServer(s *grpc.Server, srv ClusterServer) {
+	s.RegisterService(&_Cluster_serviceDesc, srv)
 }
 
-func _Storage_Add_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(KeyValue)
+func _Cluster_RequestVote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VoteRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(StorageServer).Add(ctx, in)
+		return srv.(ClusterServer).RequestVote(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.Storage/Add",
+		FullMethod: "/pb.Cluster/RequestVote",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).Add(ctx, req.(*KeyValue))
+		return srv.(ClusterServer).RequestVote(ctx, req.(*VoteRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Storage_AddWithTtl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(KeyValueTtl)
+func _Cluster_AppendEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AppendRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(StorageServer).AddWithTtl(ctx, in)
+		return srv.(ClusterServer).AppendEntries(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.Storage/AddWithTtl",
+		FullMethod: "/pb.Cluster/AppendEntries",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).AddWithTtl(ctx, req.(*KeyValueTtl))
+		return srv.(ClusterServer).AppendEntries(ctx, req.(*AppendRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Storage_Value_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Key)
+func _Cluster_InstallSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(StorageServer).Value(ctx, in)
+		return srv.(ClusterServer).InstallSnapshot(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.Storage/Value",
+		FullMethod: "/pb.Cluster/InstallSnapshot",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).Value(ctx, req.(*Key))
+		return srv.(ClusterServer).InstallSnapshot(ctx, req.(*SnapshotRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Storage_ListAll_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(Empty)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
-	}
-	return srv.(StorageServer).ListAll(m, &storageListAllServer{stream})
-}
-
-type Storage_ListAllServer interface {
-	Send(*T) error
-	grpc.ServerStream
-}
-
-type storageListAllServer struct {
-	grpc.ServerStream
-}
-
-func (x *storageListAllServer) Send(m *T) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func _Storage_Remove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Key)
+func _Cluster_AddMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Member)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(StorageServer).Remove(ctx, in)
+		return srv.(ClusterServer).AddMember(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.Storage/Remove",
+		FullMethod: "/pb.Cluster/AddMember",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).Remove(ctx, req.(*Key))
+		return srv.(ClusterServer).AddMember(ctx, req.(*Member))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Storage_TimeAlive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Key)
+func _Cluster_RemoveMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Member)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(StorageServer).TimeAlive(ctx, in)
+		return srv.(ClusterServer).RemoveMember(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.Storage/TimeAlive",
+		FullMethod: "/pb.Cluster/RemoveMember",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).TimeAlive(ctx, req.(*Key))
+		return srv.(ClusterServer).RemoveMember(ctx, req.(*Member))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Storage_SetTtl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TtlRequest)
+func _Cluster_Members_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(StorageServer).SetTtl(ctx, in)
+		return srv.(ClusterServer).Members(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.Storage/SetTtl",
+		FullMethod: "/pb.Cluster/Members",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(StorageServer).SetTtl(ctx, req.(*TtlRequest))
+		return srv.(ClusterServer).Members(ctx, req.(*Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _Storage_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "pb.Storage",
-	HandlerType: (*StorageServer)(nil),
+var _Cluster_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.Cluster",
+	HandlerType: (*ClusterServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "Add",
-			Handler:    _Storage_Add_Handler,
-		},
-		{
-			MethodName: "AddWithTtl",
-			Handler:    _Storage_AddWithTtl_Handler,
+			MethodName: "RequestVote",
+			Handler:    _Cluster_RequestVote_Handler,
 		},
 		{
-			MethodName: "Value",
-			Handler:    _Storage_Value_Handler,
+			MethodName: "AppendEntries",
+			Handler:    _Cluster_AppendEntries_Handler,
 		},
 		{
-			MethodName: "Remove",
-			Handler:    _Storage_Remove_Handler,
+			MethodName: "InstallSnapshot",
+			Handler:    _Cluster_InstallSnapshot_Handler,
 		},
 		{
-			MethodName: "TimeAlive",
-			Handler:    _Storage_TimeAlive_Handler,
+			MethodName: "AddMember",
+			Handler:    _Cluster_AddMember_Handler,
 		},
 		{
-			MethodName: "SetTtl",
-			Handler:    _Storage_SetTtl_Handler,
+			MethodName: "RemoveMember",
+			Handler:    _Cluster_RemoveMember_Handler,
 		},
-	},
-	Streams: []grpc.StreamDesc{
 		{
-			StreamName:    "ListAll",
-			Handler:       _Storage_ListAll_Handler,
-			ServerStreams: true,
+			MethodName: "Members",
+			Handler:    _Cluster_Members_Handler,
 		},
 	},
+	Streams:  []grpc.StreamDesc{},
 	Metadata: "cache.proto",
 }
@@ -0,0 +1,35 @@
+package pb
+
+// ErrorDomain is set in the ErrorInfo details of errors returned by the server.
+const ErrorDomain = "kv-ttl"
+
+// ResourceType is set in the ResourceInfo details of errors related to a key.
+const ResourceType = "key"
+
+// NamespaceResourceType is set in the ResourceInfo details of errors related to a namespace.
+const NamespaceResourceType = "namespace"
+
+// Reasons set in the ErrorInfo details of errors returned by the server.
+const (
+	ReasonNotFound        = "NOT_FOUND"
+	ReasonExists          = "EXISTS"
+	ReasonVersionMismatch = "VERSION_MISMATCH"
+	ReasonNotNumeric      = "NOT_NUMERIC"
+	ReasonOverflow        = "OVERFLOW"
+	ReasonInvalidTtl      = "INVALID_TTL"
+	ReasonInvalidKey      = "INVALID_KEY"
+	ReasonTooLarge        = "TOO_LARGE"
+	ReasonConflict        = "CONFLICT"
+	ReasonLocked          = "LOCKED"
+	ReasonNotOwner        = "NOT_OWNER"
+
+	ReasonNamespaceNotFound = "NAMESPACE_NOT_FOUND"
+	ReasonNamespaceExists   = "NAMESPACE_EXISTS"
+	ReasonInvalidNamespace  = "INVALID_NAMESPACE"
+
+	// ReasonReadOnly is set for writes rejected by followers.
+	// The address of the leader is set in the metadata under the "leader" key.
+	ReasonReadOnly = "READ_ONLY"
+	// ReasonUnavailable is set when the storage failed to record the change, it wasn't made.
+	ReasonUnavailable = "UNAVAILABLE"
+)
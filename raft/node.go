@@ -0,0 +1,1120 @@
+// Package raft runs a cache as a node of a cluster that agrees on every change using
+// the Raft consensus algorithm. Every change of the cache, including expirations and
+// evictions, is appended to the replicated log by the leader and applied by the other
+// nodes once a majority of the members has stored it, so the nodes never diverge.
+package raft
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"kv-ttl/kv"
+	"kv-ttl/pb"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultElectionTimeout   = 500 * time.Millisecond
+	DefaultHeartbeatInterval = 100 * time.Millisecond
+	DefaultSnapshotThreshold = 1024
+	DefaultCommitTimeout     = 2 * time.Second
+)
+
+// maxAppendEntries limits the number of entries sent in a single AppendEntries call.
+const maxAppendEntries = 256
+
+var (
+	// ErrMembershipChange is returned when the previous membership change hasn't been committed yet.
+	ErrMembershipChange = errors.New("another membership change is in progress")
+	// ErrCommitTimeout is returned when the change wasn't committed in Config.CommitTimeout.
+	// The change may still be committed later. It wraps kv.ErrUnavailable.
+	ErrCommitTimeout = fmt.Errorf("commit timed out, the change may or may not be applied: %w", kv.ErrUnavailable)
+	// ErrLeadershipLost is returned when the node stopped being the leader before the change
+	// was committed. The change may still be committed by the new leader. It wraps kv.ErrUnavailable.
+	ErrLeadershipLost = fmt.Errorf("leadership lost, the change may or may not be applied: %w", kv.ErrUnavailable)
+	// ErrNotLogStorage is returned by NewNode for a storage that doesn't implement kv.LogStorage.
+	ErrNotLogStorage = errors.New("the storage of the node must implement kv.LogStorage")
+	// ErrStopped is returned when the node is closed. It wraps kv.ErrUnavailable.
+	ErrStopped = fmt.Errorf("node is stopped: %w", kv.ErrUnavailable)
+)
+
+// Member is a node of the cluster.
+type Member struct {
+	ID      string `json:"id"`
+	Address string `json:"address"`
+}
+
+// Config configures a Node.
+type Config struct {
+	// ID identifies the node in the cluster, Address is where the other nodes reach it.
+	ID      string
+	Address string
+	// Storage keeps the log and the snapshots of the node, it must implement kv.LogStorage
+	// so that only the changes are written. The node keeps nothing if it is not set.
+	Storage   kv.Storage
+	Transport Transport
+	// Bootstrap starts a new cluster with this node as its only member.
+	// It is ignored if the storage holds the state of the node already.
+	Bootstrap bool
+	// ElectionTimeout is the time without the leader after which a member starts an election,
+	// the actual timeout is randomized between it and its double.
+	ElectionTimeout time.Duration
+	// HeartbeatInterval is the period between the calls of the leader to the idle members.
+	HeartbeatInterval time.Duration
+	// SnapshotThreshold is the number of applied entries after which the log is compacted.
+	SnapshotThreshold uint64
+	// CommitTimeout limits the time a change of the cache waits to be committed,
+	// the keys it changes stay locked meanwhile.
+	CommitTimeout time.Duration
+	// Cache configures the cache of the node, its storage is replaced by the replicated log.
+	Cache kv.Configuration
+}
+
+type role int
+
+const (
+	follower role = iota
+	candidate
+	leader
+)
+
+// proposal is a change appended by the leader waiting to be committed.
+type proposal struct {
+	term uint64
+	done chan error
+}
+
+// Node is a member of the cluster holding a replica of the cache.
+// Only the leader changes the cache, the changes made on the other nodes fail
+// with kv.ErrReadOnly, see Writable.
+type Node struct {
+	config Config
+	cache  kv.Cache
+	// replica is the cache applying the changes committed by the log.
+	replica kv.Replica
+	store   *persister
+	rnd     *rand.Rand
+
+	// applyMu serializes the changes of the cache made by the log.
+	applyMu sync.Mutex
+
+	mu       sync.Mutex
+	role     role
+	term     uint64
+	votedFor string
+	leaderID string
+	// log holds the entries following the snapshot.
+	log  []entry
+	snap snapshot
+	// members is the latest membership in the log, it takes effect once appended.
+	members      []Member
+	membersIndex uint64
+	commitIndex  uint64
+	lastApplied  uint64
+	// readyIndex is the index of the first entry of the leader's term.
+	// The leader accepts changes once it is applied, so that all the entries
+	// of the previous leaders are applied before.
+	readyIndex   uint64
+	nextIndex    map[string]uint64
+	matchIndex   map[string]uint64
+	lastContact  map[string]time.Time
+	inflight     map[string]bool
+	votes        int
+	heard        time.Time
+	deadline     time.Time
+	pending      map[uint64]*proposal
+	snapshotting bool
+	stopped      bool
+
+	applyCh chan struct{}
+	done    chan struct{}
+	workers sync.WaitGroup
+}
+
+// NewNode restores the node from its storage, creates its cache and starts taking part
+// in the cluster.
+func NewNode(config Config) (*Node, error) {
+	if config.ElectionTimeout <= 0 {
+		config.ElectionTimeout = DefaultElectionTimeout
+	}
+	if config.HeartbeatInterval <= 0 {
+		config.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+	if config.SnapshotThreshold == 0 {
+		config.SnapshotThreshold = DefaultSnapshotThreshold
+	}
+	if config.CommitTimeout <= 0 {
+		config.CommitTimeout = DefaultCommitTimeout
+	}
+	var logStorage kv.LogStorage
+	switch s := config.Storage.(type) {
+	case nil, *kv.UnimplementedStorage:
+	case kv.LogStorage:
+		logStorage = s
+	default:
+		return nil, ErrNotLogStorage
+	}
+	store, err := newPersister(logStorage)
+	if err != nil {
+		return nil, err
+	}
+	state, snap, entries, err := store.restore()
+	if err != nil {
+		return nil, err
+	}
+	n := &Node{
+		config:      config,
+		store:       store,
+		rnd:         rand.New(rand.NewSource(time.Now().UnixNano())),
+		term:        state.Term,
+		votedFor:    state.VotedFor,
+		log:         entries,
+		snap:        snap,
+		commitIndex: snap.Index,
+		lastApplied: snap.Index,
+		nextIndex:   make(map[string]uint64),
+		matchIndex:  make(map[string]uint64),
+		lastContact: make(map[string]time.Time),
+		inflight:    make(map[string]bool),
+		pending:     make(map[uint64]*proposal),
+		applyCh:     make(chan struct{}, 1),
+		done:        make(chan struct{}),
+	}
+	n.refreshMembers()
+	if config.Bootstrap && n.lastIndex() == 0 && n.term == 0 {
+		data, err := json.Marshal([]Member{{ID: config.ID, Address: config.Address}})
+		if err != nil {
+			return nil, err
+		}
+		n.term = 1
+		if err := n.persistState(); err != nil {
+			return nil, err
+		}
+		if err := n.appendLog(entry{Index: 1, Term: 1, Kind: pb.RaftEntry_MEMBERS, Data: data}); err != nil {
+			return nil, err
+		}
+	}
+	n.resetDeadline()
+
+	cacheConfig := config.Cache
+	cacheConfig.Storage = &nodeLog{n: n}
+	cacheConfig.BackupInterval = 0
+	n.cache = kv.NewCache(cacheConfig)
+	n.replica = n.cache.(kv.Replica)
+
+	n.workers.Add(2)
+	go n.run()
+	go n.applyLoop()
+	return n, nil
+}
+
+// Cache returns the cache replicated by the node.
+func (n *Node) Cache() kv.Cache {
+	return n.cache
+}
+
+// ID returns the id of the node.
+func (n *Node) ID() string {
+	return n.config.ID
+}
+
+// Close stops the node, closes its cache and its storage if it implements io.Closer.
+// The changes waiting to be committed fail with ErrStopped.
+func (n *Node) Close(ctx context.Context) error {
+	n.mu.Lock()
+	if n.stopped {
+		n.mu.Unlock()
+		return nil
+	}
+	n.stopped = true
+	n.failPending(ErrStopped)
+	close(n.done)
+	n.mu.Unlock()
+	n.workers.Wait()
+	err := n.cache.Close(ctx)
+	if closer, ok := n.config.Storage.(io.Closer); ok {
+		if cErr := closer.Close(); err == nil {
+			err = cErr
+		}
+	}
+	return err
+}
+
+// Writable reports whether the node is the leader ready to accept changes.
+func (n *Node) Writable() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.writable()
+}
+
+func (n *Node) writable() bool {
+	return n.role == leader && n.lastApplied >= n.readyIndex && !n.stopped
+}
+
+// Leader returns the address of the current leader, empty if it is unknown.
+func (n *Node) Leader() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, m := range n.members {
+		if m.ID == n.leaderID {
+			return m.Address
+		}
+	}
+	return ""
+}
+
+// Connected reports whether the node is the leader or has heard from the leader recently.
+func (n *Node) Connected() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.role == leader {
+		return true
+	}
+	return n.leaderID != "" && time.Since(n.heard) < n.config.ElectionTimeout
+}
+
+// Lag returns the time since the node heard from the leader, zero for the leader itself.
+func (n *Node) Lag() time.Duration {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.role == leader || n.heard.IsZero() {
+		return 0
+	}
+	return time.Since(n.heard)
+}
+
+// Members returns the current members and the id of the leader if it is known.
+func (n *Node) Members() ([]Member, string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	members := make([]Member, len(n.members))
+	copy(members, n.members)
+	return members, n.leaderID
+}
+
+// AddMember adds the node to the cluster or changes its address and waits until
+// the change is committed. Members are changed one at a time by the leader,
+// kv.ErrReadOnly is returned by the other nodes.
+func (n *Node) AddMember(m Member) error {
+	return n.changeMembers(func(members []Member) []Member {
+		for i := range members {
+			if members[i].ID == m.ID {
+				members[i].Address = m.Address
+				return members
+			}
+		}
+		return append(members, m)
+	})
+}
+
+// RemoveMember removes the node from the cluster, see AddMember.
+// The leader removing itself steps down once the change is committed.
+func (n *Node) RemoveMember(id string) error {
+	return n.changeMembers(func(members []Member) []Member {
+		kept := members[:0]
+		for _, m := range members {
+			if m.ID != id {
+				kept = append(kept, m)
+			}
+		}
+		return kept
+	})
+}
+
+func (n *Node) changeMembers(change func([]Member) []Member) error {
+	n.mu.Lock()
+	if !n.writable() {
+		n.mu.Unlock()
+		return kv.ErrReadOnly
+	}
+	if n.membersIndex > n.commitIndex {
+		n.mu.Unlock()
+		return ErrMembershipChange
+	}
+	members := make([]Member, len(n.members))
+	copy(members, n.members)
+	data, err := json.Marshal(change(members))
+	if err != nil {
+		n.mu.Unlock()
+		return err
+	}
+	done, err := n.propose(pb.RaftEntry_MEMBERS, data)
+	n.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return <-done
+}
+
+// submit appends the changes of the cache to the log and waits until they are committed
+// or Config.CommitTimeout passes. A change that timed out is applied like the ones of
+// other leaders if it is committed later.
+func (n *Node) submit(changes []kv.Change) error {
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	n.mu.Lock()
+	if !n.writable() {
+		n.mu.Unlock()
+		return kv.ErrReadOnly
+	}
+	done, err := n.propose(pb.RaftEntry_CHANGES, data)
+	index := n.lastIndex()
+	n.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	timer := time.NewTimer(n.config.CommitTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+	}
+	n.mu.Lock()
+	if p, ok := n.pending[index]; ok && p.done == done {
+		delete(n.pending, index)
+		n.mu.Unlock()
+		return ErrCommitTimeout
+	}
+	n.mu.Unlock()
+	// The proposal was completed meanwhile, the result is on its way.
+	return <-done
+}
+
+// propose appends the entry to the log of the leader and starts replicating it.
+// Must be called with the lock held.
+func (n *Node) propose(kind pb.RaftEntry_Kind, data []byte) (chan error, error) {
+	e := entry{Index: n.lastIndex() + 1, Term: n.term, Kind: kind, Data: data}
+	if err := n.appendLog(e); err != nil {
+		return nil, err
+	}
+	done := make(chan error, 1)
+	n.pending[e.Index] = &proposal{term: e.Term, done: done}
+	n.advanceCommit()
+	n.broadcast()
+	return done, nil
+}
+
+// failPending fails the proposals waiting to be committed. Must be called with the lock held.
+func (n *Node) failPending(err error) {
+	for index, p := range n.pending {
+		p.done <- err
+		delete(n.pending, index)
+	}
+}
+
+// run starts elections and sends heartbeats until the node is closed.
+func (n *Node) run() {
+	defer n.workers.Done()
+	ticker := time.NewTicker(n.config.HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-n.done:
+			return
+		case <-ticker.C:
+		}
+		n.mu.Lock()
+		now := time.Now()
+		switch {
+		case n.role == leader && !n.quorumContacted(now):
+			log.Printf("raft: %s lost contact with the majority in term %d\n", n.config.ID, n.term)
+			n.becomeFollower(n.term, "")
+		case n.role == leader:
+			n.broadcast()
+		case now.After(n.deadline) && n.isMember(n.config.ID):
+			n.startElection()
+		}
+		n.mu.Unlock()
+	}
+}
+
+// quorumContacted reports whether the leader heard from the majority of the members
+// within the election timeout. Must be called with the lock held.
+func (n *Node) quorumContacted(now time.Time) bool {
+	contacted := 0
+	for _, m := range n.members {
+		if m.ID == n.config.ID || now.Sub(n.lastContact[m.ID]) < n.config.ElectionTimeout {
+			contacted++
+		}
+	}
+	return contacted > len(n.members)/2
+}
+
+func (n *Node) resetDeadline() {
+	timeout := n.config.ElectionTimeout + time.Duration(n.rnd.Int63n(int64(n.config.ElectionTimeout)))
+	n.deadline = time.Now().Add(timeout)
+}
+
+// startElection makes the node a candidate and requests the votes of the other members.
+// Must be called with the lock held.
+func (n *Node) startElection() {
+	n.role = candidate
+	n.term++
+	n.votedFor = n.config.ID
+	n.leaderID = ""
+	n.votes = 1
+	n.resetDeadline()
+	if err := n.persistState(); err != nil {
+		log.Println(err)
+		return
+	}
+	if n.votes > len(n.members)/2 {
+		n.becomeLeader()
+		return
+	}
+	req := &pb.VoteRequest{
+		Term:         n.term,
+		Candidate:    n.config.ID,
+		LastLogIndex: n.lastIndex(),
+		LastLogTerm:  n.termAt(n.lastIndex()),
+	}
+	for _, m := range n.members {
+		if m.ID == n.config.ID {
+			continue
+		}
+		go n.requestVote(m, req)
+	}
+}
+
+func (n *Node) requestVote(m Member, req *pb.VoteRequest) {
+	ctx, cancel := context.WithTimeout(context.Background(), n.config.ElectionTimeout)
+	defer cancel()
+	resp, err := n.config.Transport.RequestVote(ctx, m.Address, req)
+	if err != nil {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if resp.Term > n.term {
+		n.becomeFollower(resp.Term, "")
+		return
+	}
+	if n.role != candidate || n.term != req.Term || !resp.Granted {
+		return
+	}
+	n.votes++
+	if n.votes > len(n.members)/2 {
+		n.becomeLeader()
+	}
+}
+
+// becomeLeader appends an empty entry of the new term, the leader accepts changes
+// once it is applied. Must be called with the lock held.
+func (n *Node) becomeLeader() {
+	log.Printf("raft: %s is the leader in term %d\n", n.config.ID, n.term)
+	n.role = leader
+	n.leaderID = n.config.ID
+	now := time.Now()
+	for _, m := range n.members {
+		n.nextIndex[m.ID] = n.lastIndex() + 1
+		n.matchIndex[m.ID] = 0
+		n.lastContact[m.ID] = now
+	}
+	n.readyIndex = n.lastIndex() + 1
+	if _, err := n.propose(pb.RaftEntry_NOOP, nil); err != nil {
+		log.Println(err)
+		n.becomeFollower(n.term, "")
+	}
+}
+
+// becomeFollower moves the node to the term if it is newer and fails the proposals
+// of the former leader. Must be called with the lock held.
+func (n *Node) becomeFollower(term uint64, leaderID string) {
+	if term > n.term {
+		n.term = term
+		n.votedFor = ""
+		if err := n.persistState(); err != nil {
+			log.Println(err)
+		}
+	}
+	if n.role == leader {
+		n.failPending(ErrLeadershipLost)
+	}
+	n.role = follower
+	n.leaderID = leaderID
+	n.resetDeadline()
+}
+
+// broadcast replicates the log to the members that are not being sent anything already.
+// Must be called with the lock held.
+func (n *Node) broadcast() {
+	for _, m := range n.members {
+		if m.ID == n.config.ID || n.inflight[m.ID] {
+			continue
+		}
+		if _, ok := n.nextIndex[m.ID]; !ok {
+			n.nextIndex[m.ID] = n.lastIndex() + 1
+			n.lastContact[m.ID] = time.Now()
+		}
+		n.inflight[m.ID] = true
+		go n.replicate(m)
+	}
+}
+
+// replicate sends the entries missing on the member until it is up to date
+// or the call fails. Sends a heartbeat if the member is up to date already.
+func (n *Node) replicate(m Member) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	defer func() { n.inflight[m.ID] = false }()
+	for n.role == leader && !n.stopped && n.isMember(m.ID) {
+		term := n.term
+		if n.nextIndex[m.ID] <= n.snap.Index {
+			if !n.sendSnapshot(m) {
+				return
+			}
+			continue
+		}
+		prev := n.nextIndex[m.ID] - 1
+		entries := n.entriesFrom(prev+1, maxAppendEntries)
+		req := &pb.AppendRequest{
+			Term:         term,
+			Leader:       n.config.ID,
+			PrevLogIndex: prev,
+			PrevLogTerm:  n.termAt(prev),
+			Entries:      make([]*pb.RaftEntry, len(entries)),
+			LeaderCommit: n.commitIndex,
+		}
+		for i, e := range entries {
+			req.Entries[i] = &pb.RaftEntry{Index: e.Index, Term: e.Term, Kind: e.Kind, Data: e.Data}
+		}
+		n.mu.Unlock()
+		ctx, cancel := context.WithTimeout(context.Background(), n.config.ElectionTimeout)
+		resp, err := n.config.Transport.AppendEntries(ctx, m.Address, req)
+		cancel()
+		n.mu.Lock()
+		if err != nil {
+			return
+		}
+		if resp.Term > n.term {
+			n.becomeFollower(resp.Term, "")
+			return
+		}
+		if n.role != leader || n.term != term {
+			return
+		}
+		n.lastContact[m.ID] = time.Now()
+		if !resp.Success {
+			next := prev
+			if resp.LastLogIndex+1 < next {
+				next = resp.LastLogIndex + 1
+			}
+			if next < 1 {
+				next = 1
+			}
+			n.nextIndex[m.ID] = next
+			continue
+		}
+		match := prev + uint64(len(entries))
+		if match > n.matchIndex[m.ID] {
+			n.matchIndex[m.ID] = match
+		}
+		n.nextIndex[m.ID] = match + 1
+		n.advanceCommit()
+		if match >= n.lastIndex() {
+			return
+		}
+	}
+}
+
+// sendSnapshot sends the snapshot to the member lagging behind the compacted log.
+// Called with the lock held, releases it during the call. Reports whether the call succeeded.
+func (n *Node) sendSnapshot(m Member) bool {
+	term := n.term
+	req := &pb.SnapshotRequest{
+		Term:      term,
+		Leader:    n.config.ID,
+		LastIndex: n.snap.Index,
+		LastTerm:  n.snap.Term,
+		Members:   make([]*pb.Member, len(n.snap.Members)),
+		Data:      n.snap.Data,
+	}
+	for i, member := range n.snap.Members {
+		req.Members[i] = &pb.Member{Id: member.ID, Address: member.Address}
+	}
+	n.mu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*n.config.ElectionTimeout)
+	resp, err := n.config.Transport.InstallSnapshot(ctx, m.Address, req)
+	cancel()
+	n.mu.Lock()
+	if err != nil {
+		log.Println("raft: snapshot not installed:", err)
+		return false
+	}
+	if resp.Term > n.term {
+		n.becomeFollower(resp.Term, "")
+		return false
+	}
+	if n.role != leader || n.term != term {
+		return false
+	}
+	n.lastContact[m.ID] = time.Now()
+	if req.LastIndex > n.matchIndex[m.ID] {
+		n.matchIndex[m.ID] = req.LastIndex
+	}
+	n.nextIndex[m.ID] = n.matchIndex[m.ID] + 1
+	return true
+}
+
+// advanceCommit commits the entries of the current term stored by the majority.
+// Must be called with the lock held.
+func (n *Node) advanceCommit() {
+	for index := n.lastIndex(); index > n.commitIndex; index-- {
+		if n.termAt(index) != n.term {
+			break
+		}
+		stored := 0
+		for _, m := range n.members {
+			if m.ID == n.config.ID || n.matchIndex[m.ID] >= index {
+				stored++
+			}
+		}
+		if stored > len(n.members)/2 {
+			n.commitIndex = index
+			n.notifyApply()
+			break
+		}
+	}
+	if n.role == leader && n.membersIndex <= n.commitIndex && !n.isMember(n.config.ID) {
+		log.Printf("raft: %s was removed from the cluster\n", n.config.ID)
+		n.becomeFollower(n.term, "")
+	}
+}
+
+func (n *Node) notifyApply() {
+	select {
+	case n.applyCh <- struct{}{}:
+	default:
+	}
+}
+
+// applyLoop applies the committed entries to the cache. The changes proposed by the cache
+// of this node are applied by the cache itself once the proposal is committed.
+func (n *Node) applyLoop() {
+	defer n.workers.Done()
+	for {
+		select {
+		case <-n.done:
+			return
+		case <-n.applyCh:
+		}
+		for n.applyCommitted() {
+		}
+	}
+}
+
+// applyCommitted applies the entries committed so far, reports whether there were any.
+func (n *Node) applyCommitted() bool {
+	n.mu.Lock()
+	if n.lastApplied >= n.commitIndex || n.stopped {
+		n.mu.Unlock()
+		return false
+	}
+	entries := n.entriesFrom(n.lastApplied+1, int(n.commitIndex-n.lastApplied))
+	n.mu.Unlock()
+
+	n.applyMu.Lock()
+	defer n.applyMu.Unlock()
+	for _, e := range entries {
+		n.mu.Lock()
+		if e.Index <= n.lastApplied {
+			n.mu.Unlock()
+			continue
+		}
+		p, owned := n.pending[e.Index]
+		delete(n.pending, e.Index)
+		n.mu.Unlock()
+
+		if owned && p.term == e.Term {
+			p.done <- nil
+		} else if e.Kind == pb.RaftEntry_CHANGES {
+			var changes []kv.Change
+			if err := json.Unmarshal(e.Data, &changes); err != nil {
+				log.Println(err)
+			} else {
+				n.replica.ApplyChanges(changes...)
+			}
+		}
+
+		n.mu.Lock()
+		n.lastApplied = e.Index
+		if n.lastApplied-n.snap.Index >= n.config.SnapshotThreshold && !n.snapshotting {
+			n.snapshotting = true
+			go n.takeSnapshot()
+		}
+		n.mu.Unlock()
+	}
+	return true
+}
+
+// takeSnapshot replaces the applied entries of the log with the copy of the cache.
+func (n *Node) takeSnapshot() {
+	n.mu.Lock()
+	index := n.lastApplied
+	snap := snapshot{Index: index, Term: n.termAt(index), Members: n.membersAt(index)}
+	n.mu.Unlock()
+
+	// The copy contains all the changes up to the index and maybe some of the following ones,
+	// the entries following the snapshot are idempotent, so applying them again does no harm.
+	data, err := json.Marshal(n.replica.Copy())
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.snapshotting = false
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if index <= n.snap.Index {
+		return
+	}
+	snap.Data = data
+	if err := n.saveSnapshot(snap); err != nil {
+		log.Println(err)
+	}
+}
+
+// saveSnapshot replaces the log entries up to the snapshot index with the snapshot.
+// Must be called with the lock held.
+func (n *Node) saveSnapshot(snap snapshot) error {
+	change, err := set(snapshotKey, snap)
+	if err != nil {
+		return err
+	}
+	changes := []kv.Change{change}
+	kept := make([]entry, 0, len(n.log))
+	for _, e := range n.log {
+		if e.Index <= snap.Index {
+			changes = append(changes, kv.Change{Kind: kv.ChangeDelete, Key: entryKey(e.Index)})
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	if err := n.store.write(changes...); err != nil {
+		return err
+	}
+	n.log = kept
+	n.snap = snap
+	return nil
+}
+
+// RequestVote handles the vote request of a candidate.
+func (n *Node) RequestVote(req *pb.VoteRequest) *pb.VoteResponse {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	// Members that have heard from the leader recently ignore candidates,
+	// so removed members and nodes with flaky connections don't disrupt the cluster.
+	if n.role == leader || n.leaderID != "" && time.Since(n.heard) < n.config.ElectionTimeout {
+		return &pb.VoteResponse{Term: n.term}
+	}
+	if req.Term < n.term || n.stopped {
+		return &pb.VoteResponse{Term: n.term}
+	}
+	if req.Term > n.term {
+		n.becomeFollower(req.Term, "")
+	}
+	lastIndex := n.lastIndex()
+	lastTerm := n.termAt(lastIndex)
+	upToDate := req.LastLogTerm > lastTerm || req.LastLogTerm == lastTerm && req.LastLogIndex >= lastIndex
+	if !upToDate || n.votedFor != "" && n.votedFor != req.Candidate {
+		return &pb.VoteResponse{Term: n.term}
+	}
+	n.votedFor = req.Candidate
+	if err := n.persistState(); err != nil {
+		log.Println(err)
+		return &pb.VoteResponse{Term: n.term}
+	}
+	n.resetDeadline()
+	return &pb.VoteResponse{Term: n.term, Granted: true}
+}
+
+// AppendEntries handles the entries sent by the leader.
+func (n *Node) AppendEntries(req *pb.AppendRequest) *pb.AppendResponse {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if req.Term < n.term || n.stopped {
+		return &pb.AppendResponse{Term: n.term, LastLogIndex: n.lastIndex()}
+	}
+	n.follow(req.Term, req.Leader)
+	resp := &pb.AppendResponse{Term: n.term}
+	if req.PrevLogIndex > n.lastIndex() {
+		resp.LastLogIndex = n.lastIndex()
+		return resp
+	}
+	if req.PrevLogIndex >= n.snap.Index && n.termAt(req.PrevLogIndex) != req.PrevLogTerm {
+		resp.LastLogIndex = req.PrevLogIndex - 1
+		return resp
+	}
+	var added []entry
+	for i, pe := range req.Entries {
+		if pe.Index <= n.snap.Index {
+			continue
+		}
+		if pe.Index <= n.lastIndex() {
+			if n.termAt(pe.Index) == pe.Term {
+				continue
+			}
+			if err := n.truncateFrom(pe.Index); err != nil {
+				log.Println(err)
+				resp.LastLogIndex = n.lastIndex()
+				return resp
+			}
+		}
+		for _, pe := range req.Entries[i:] {
+			added = append(added, entry{Index: pe.Index, Term: pe.Term, Kind: pe.Kind, Data: pe.Data})
+		}
+		break
+	}
+	if err := n.appendLog(added...); err != nil {
+		log.Println(err)
+		resp.LastLogIndex = n.lastIndex()
+		return resp
+	}
+	lastNew := req.PrevLogIndex + uint64(len(req.Entries))
+	if req.LeaderCommit > n.commitIndex {
+		n.commitIndex = req.LeaderCommit
+		if lastNew < n.commitIndex {
+			n.commitIndex = lastNew
+		}
+		n.notifyApply()
+	}
+	resp.Success = true
+	resp.LastLogIndex = n.lastIndex()
+	return resp
+}
+
+// InstallSnapshot replaces the content of the cache with the snapshot sent by the leader.
+func (n *Node) InstallSnapshot(req *pb.SnapshotRequest) *pb.SnapshotResponse {
+	n.applyMu.Lock()
+	defer n.applyMu.Unlock()
+	n.mu.Lock()
+	if req.Term < n.term || n.stopped {
+		defer n.mu.Unlock()
+		return &pb.SnapshotResponse{Term: n.term}
+	}
+	n.follow(req.Term, req.Leader)
+	resp := &pb.SnapshotResponse{Term: n.term}
+	if req.LastIndex <= n.lastApplied {
+		n.mu.Unlock()
+		return resp
+	}
+	snap := snapshot{Index: req.LastIndex, Term: req.LastTerm, Members: make([]Member, len(req.Members)), Data: req.Data}
+	for i, m := range req.Members {
+		snap.Members[i] = Member{ID: m.Id, Address: m.Address}
+	}
+	if n.termAt(req.LastIndex) != req.LastTerm {
+		// The log doesn't match the snapshot, all of it is replaced.
+		if err := n.truncateFrom(n.snap.Index + 1); err != nil {
+			log.Println(err)
+			n.mu.Unlock()
+			return resp
+		}
+	}
+	if err := n.saveSnapshot(snap); err != nil {
+		log.Println(err)
+		n.mu.Unlock()
+		return resp
+	}
+	n.refreshMembers()
+	if n.commitIndex < snap.Index {
+		n.commitIndex = snap.Index
+	}
+	n.mu.Unlock()
+
+	var entries map[string]kv.TtlBox
+	if err := json.Unmarshal(req.Data, &entries); err != nil {
+		log.Println(err)
+	}
+	current := n.replica.Copy()
+	changes := make([]kv.Change, 0, len(entries))
+	for k := range current {
+		if _, ok := entries[k]; !ok {
+			changes = append(changes, kv.Change{Kind: kv.ChangeDelete, Key: k})
+		}
+	}
+	for k, box := range entries {
+		changes = append(changes, kv.Change{Kind: kv.ChangeSet, Key: k, Box: box})
+	}
+	n.replica.ApplyChanges(changes...)
+
+	n.mu.Lock()
+	if n.lastApplied < snap.Index {
+		n.lastApplied = snap.Index
+	}
+	n.mu.Unlock()
+	n.notifyApply()
+	return resp
+}
+
+// follow makes the node a follower of the leader of the term.
+// Must be called with the lock held.
+func (n *Node) follow(term uint64, leaderID string) {
+	if term > n.term || n.role != follower || n.leaderID != leaderID {
+		n.becomeFollower(term, leaderID)
+	}
+	n.heard = time.Now()
+	n.resetDeadline()
+}
+
+// appendLog stores the entries after the last one. Membership entries take effect right away.
+// Must be called with the lock held.
+func (n *Node) appendLog(entries ...entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	changes := make([]kv.Change, len(entries))
+	for i, e := range entries {
+		change, err := set(entryKey(e.Index), e)
+		if err != nil {
+			return err
+		}
+		changes[i] = change
+	}
+	if err := n.store.write(changes...); err != nil {
+		return err
+	}
+	n.log = append(n.log, entries...)
+	n.refreshMembers()
+	return nil
+}
+
+// truncateFrom deletes the entries starting with the index, they conflict with the leader's log.
+// Must be called with the lock held.
+func (n *Node) truncateFrom(index uint64) error {
+	var changes []kv.Change
+	kept := n.log[:0]
+	for _, e := range n.log {
+		if e.Index >= index {
+			changes = append(changes, kv.Change{Kind: kv.ChangeDelete, Key: entryKey(e.Index)})
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	if err := n.store.write(changes...); err != nil {
+		return err
+	}
+	n.log = kept
+	n.refreshMembers()
+	return nil
+}
+
+// refreshMembers takes the latest membership from the log or the snapshot.
+// Must be called with the lock held.
+func (n *Node) refreshMembers() {
+	n.members, n.membersIndex = n.snap.Members, n.snap.Index
+	for i := len(n.log) - 1; i >= 0; i-- {
+		if n.log[i].Kind == pb.RaftEntry_MEMBERS {
+			var members []Member
+			if err := json.Unmarshal(n.log[i].Data, &members); err != nil {
+				log.Println(err)
+				continue
+			}
+			n.members, n.membersIndex = members, n.log[i].Index
+			return
+		}
+	}
+}
+
+// membersAt returns the membership in effect at the index. Must be called with the lock held.
+func (n *Node) membersAt(index uint64) []Member {
+	for i := len(n.log) - 1; i >= 0; i-- {
+		e := n.log[i]
+		if e.Index <= index && e.Kind == pb.RaftEntry_MEMBERS {
+			var members []Member
+			if err := json.Unmarshal(e.Data, &members); err == nil {
+				return members
+			}
+		}
+	}
+	return n.snap.Members
+}
+
+func (n *Node) isMember(id string) bool {
+	for _, m := range n.members {
+		if m.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *Node) persistState() error {
+	change, err := set(stateKey, persistentState{Term: n.term, VotedFor: n.votedFor})
+	if err != nil {
+		return err
+	}
+	return n.store.write(change)
+}
+
+func (n *Node) lastIndex() uint64 {
+	return n.snap.Index + uint64(len(n.log))
+}
+
+// termAt returns the term of the entry, zero if it is not in the log.
+func (n *Node) termAt(index uint64) uint64 {
+	if index == n.snap.Index {
+		return n.snap.Term
+	}
+	if index < n.snap.Index || index > n.lastIndex() {
+		return 0
+	}
+	return n.log[index-n.snap.Index-1].Term
+}
+
+// entriesFrom returns up to max entries starting with the index.
+func (n *Node) entriesFrom(index uint64, max int) []entry {
+	if index <= n.snap.Index || index > n.lastIndex() {
+		return nil
+	}
+	entries := n.log[index-n.snap.Index-1:]
+	if len(entries) > max {
+		entries = entries[:max]
+	}
+	result := make([]entry, len(entries))
+	copy(result, entries)
+	return result
+}
+
+// nodeLog passes the changes of the cache to the replicated log.
+type nodeLog struct {
+	n *Node
+}
+
+// RestoreInto restores the cache from the snapshot, the following entries
+// are applied once they are known to be committed.
+func (l *nodeLog) RestoreInto(values *map[string]kv.TtlBox) error {
+	l.n.mu.Lock()
+	data := l.n.snap.Data
+	l.n.mu.Unlock()
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, values)
+}
+
+// Save does nothing, the node makes snapshots on its own.
+func (l *nodeLog) Save(map[string]kv.TtlBox) error {
+	return nil
+}
+
+// Append waits until the changes are committed, fails with kv.ErrReadOnly
+// if the node is not the leader.
+func (l *nodeLog) Append(changes ...kv.Change) error {
+	return l.n.submit(changes)
+}
+
+func (l *nodeLog) Sync() error {
+	return nil
+}
+
+func (l *nodeLog) Rotate() error {
+	return nil
+}
@@ -0,0 +1,278 @@
+package raft
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"kv-ttl/kv"
+	"kv-ttl/pb"
+	"kv-ttl/repository"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func startSingle(t *testing.T, fileName string) *Node {
+	t.Helper()
+	node, err := NewNode(Config{
+		ID:                "n1",
+		Address:           "n1",
+		Storage:           repository.NewWalRepo(fileName),
+		Bootstrap:         true,
+		ElectionTimeout:   50 * time.Millisecond,
+		HeartbeatInterval: 10 * time.Millisecond,
+		SnapshotThreshold: 10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !node.Writable() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the leadership")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return node
+}
+
+func TestRestart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "raft")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fileName := filepath.Join(dir, "raft.json")
+
+	node := startSingle(t, fileName)
+	for _, k := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		if err := node.Cache().Add(k, kv.T{V: []byte(k)}); err != nil {
+			t.Fatalf("expected %s to be added, got %v", k, err)
+		}
+	}
+	node.Cache().Remove("a")
+	expected, _ := node.Cache().Entry("l")
+	if err := node.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The snapshot and the entries following it are restored.
+	node = startSingle(t, fileName)
+	defer node.Close(context.Background())
+	if _, ok := node.Cache().Value("a"); ok {
+		t.Error("expected a to stay deleted")
+	}
+	if box, ok := node.Cache().Entry("l"); !ok || box.Version != expected.Version {
+		t.Errorf("expected %v to be restored, got %v", expected, box)
+	}
+	if n := len(node.Cache().ListAll()); n != 11 {
+		t.Errorf("expected 11 keys, got %d", n)
+	}
+	if err := node.Cache().Add("m", kv.T{V: []byte("m")}); err != nil {
+		t.Errorf("expected the restarted node to accept writes, got %v", err)
+	}
+}
+
+func TestRestartState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "raft")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fileName := filepath.Join(dir, "raft.json")
+
+	node := startSingle(t, fileName)
+	for _, k := range []string{"a", "b", "c"} {
+		if err := node.Cache().Add(k, kv.T{V: []byte(k)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := node.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The term, the vote and the entries are read back from the log storage.
+	store, err := newPersister(repository.NewWalRepo(fileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, snap, entries, err := store.restore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Term != node.term || state.VotedFor != node.votedFor {
+		t.Errorf("expected term %d and vote %q, got %v", node.term, node.votedFor, state)
+	}
+	if snap.Index != node.snap.Index || len(entries) != len(node.log) {
+		t.Fatalf("expected snapshot %d and %d entries, got %d and %d", node.snap.Index, len(node.log), snap.Index, len(entries))
+	}
+	for i, e := range entries {
+		if e.Index != node.log[i].Index || e.Term != node.log[i].Term || string(e.Data) != string(node.log[i].Data) {
+			t.Errorf("expected entry %v, got %v", node.log[i], e)
+		}
+	}
+
+	restarted := startSingle(t, fileName)
+	defer restarted.Close(context.Background())
+	restarted.mu.Lock()
+	term := restarted.term
+	restarted.mu.Unlock()
+	if term <= state.Term {
+		t.Errorf("expected a new term after %d, got %d", state.Term, term)
+	}
+	if n := len(restarted.Cache().ListAll()); n != 3 {
+		t.Errorf("expected 3 keys, got %d", n)
+	}
+}
+
+func TestStorageNotLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "raft")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	_, err = NewNode(Config{ID: "n1", Address: "n1", Storage: repository.NewFileRepo(filepath.Join(dir, "raft.json"))})
+	if err != ErrNotLogStorage {
+		t.Errorf("expected ErrNotLogStorage, got %v", err)
+	}
+}
+
+// memTransport delivers the calls between the nodes directly, the partitioned nodes are unreachable.
+type memTransport struct {
+	mu          sync.Mutex
+	nodes       map[string]*Node
+	partitioned map[string]bool
+}
+
+// memLink is the transport of a single node.
+type memLink struct {
+	t    *memTransport
+	from string
+}
+
+var errUnreachable = errors.New("unreachable")
+
+func (t *memTransport) partition(id string, partitioned bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.partitioned[id] = partitioned
+}
+
+func (l memLink) node(address string) (*Node, error) {
+	l.t.mu.Lock()
+	defer l.t.mu.Unlock()
+	n, ok := l.t.nodes[address]
+	if !ok || l.t.partitioned[l.from] || l.t.partitioned[address] {
+		return nil, errUnreachable
+	}
+	return n, nil
+}
+
+func (l memLink) RequestVote(_ context.Context, address string, req *pb.VoteRequest) (*pb.VoteResponse, error) {
+	n, err := l.node(address)
+	if err != nil {
+		return nil, err
+	}
+	return n.RequestVote(req), nil
+}
+
+func (l memLink) AppendEntries(_ context.Context, address string, req *pb.AppendRequest) (*pb.AppendResponse, error) {
+	n, err := l.node(address)
+	if err != nil {
+		return nil, err
+	}
+	return n.AppendEntries(req), nil
+}
+
+func (l memLink) InstallSnapshot(_ context.Context, address string, req *pb.SnapshotRequest) (*pb.SnapshotResponse, error) {
+	n, err := l.node(address)
+	if err != nil {
+		return nil, err
+	}
+	return n.InstallSnapshot(req), nil
+}
+
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPartitionedLeader(t *testing.T) {
+	transport := &memTransport{nodes: make(map[string]*Node), partitioned: make(map[string]bool)}
+	nodes := make([]*Node, 0, 3)
+	for _, id := range []string{"n1", "n2", "n3"} {
+		node, err := NewNode(Config{
+			ID:                id,
+			Address:           id,
+			Transport:         memLink{t: transport, from: id},
+			Bootstrap:         id == "n1",
+			ElectionTimeout:   300 * time.Millisecond,
+			HeartbeatInterval: 20 * time.Millisecond,
+			CommitTimeout:     50 * time.Millisecond,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer node.Close(context.Background())
+		transport.mu.Lock()
+		transport.nodes[id] = node
+		transport.mu.Unlock()
+		nodes = append(nodes, node)
+	}
+	n1 := nodes[0]
+	eventually(t, "bootstrap", n1.Writable)
+	for _, id := range []string{"n2", "n3"} {
+		if err := n1.AddMember(Member{ID: id, Address: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The write of the isolated leader fails without waiting for it to step down
+	// and doesn't keep the key locked.
+	transport.partition("n1", true)
+	start := time.Now()
+	err := n1.Cache().Add("lost", kv.T{V: []byte("v")})
+	if err != ErrCommitTimeout || !errors.Is(err, kv.ErrUnavailable) {
+		t.Fatalf("expected ErrCommitTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= 300*time.Millisecond {
+		t.Errorf("expected the write to fail before the election timeout, took %v", elapsed)
+	}
+	if _, ok := n1.Cache().Value("lost"); ok {
+		t.Error("expected the failed write not to be applied")
+	}
+
+	var leader *Node
+	eventually(t, "new leader", func() bool {
+		for _, n := range nodes[1:] {
+			if n.Writable() {
+				leader = n
+				return true
+			}
+		}
+		return false
+	})
+	if err := leader.Cache().Add("kept", kv.T{V: []byte("v")}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The old leader drops its uncommitted entry and follows the new one.
+	transport.partition("n1", false)
+	eventually(t, "n1 to catch up", func() bool {
+		_, ok := n1.Cache().Value("kept")
+		return ok && n1.Leader() == leader.ID()
+	})
+	for _, n := range nodes {
+		if _, ok := n.Cache().Value("lost"); ok {
+			t.Errorf("expected %s not to have the failed write", n.ID())
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package raft
+
+import (
+	"encoding/json"
+	"fmt"
+	"kv-ttl/kv"
+	"kv-ttl/pb"
+	"sort"
+	"strings"
+)
+
+// Keys under which the state of the node is kept in the storage.
+const (
+	stateKey    = "raft:state"
+	snapshotKey = "raft:snapshot"
+	entryPrefix = "raft:entry:"
+)
+
+// compactAfter is the number of changes appended to a log storage after which
+// it is rewritten with the current state of the node.
+const compactAfter = 4096
+
+// entry is a record of the replicated log.
+type entry struct {
+	Index uint64            `json:"index"`
+	Term  uint64            `json:"term"`
+	Kind  pb.RaftEntry_Kind `json:"kind"`
+	Data  json.RawMessage   `json:"data,omitempty"`
+}
+
+// snapshot replaces the entries of the log up to Index. Data is the content of the cache.
+type snapshot struct {
+	Index   uint64          `json:"index"`
+	Term    uint64          `json:"term"`
+	Members []Member        `json:"members"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// persistentState is the state of the node that must survive restarts.
+type persistentState struct {
+	Term     uint64 `json:"term"`
+	VotedFor string `json:"votedFor"`
+}
+
+// persister keeps the state, the snapshot and the log entries of the node in a kv.LogStorage
+// as JSON values, the storage receives only the changes. Nothing is kept if the log is nil.
+type persister struct {
+	log      kv.LogStorage
+	data     map[string]kv.TtlBox
+	appended int
+}
+
+func newPersister(log kv.LogStorage) (*persister, error) {
+	p := &persister{log: log, data: make(map[string]kv.TtlBox)}
+	if log == nil {
+		return p, nil
+	}
+	if err := log.RestoreInto(&p.data); err != nil {
+		return nil, err
+	}
+	if p.data == nil {
+		p.data = make(map[string]kv.TtlBox)
+	}
+	return p, nil
+}
+
+// restore decodes the saved state, snapshot and the entries following the snapshot.
+func (p *persister) restore() (persistentState, snapshot, []entry, error) {
+	var state persistentState
+	var snap snapshot
+	if box, ok := p.data[stateKey]; ok {
+		if err := json.Unmarshal(box.Content.V, &state); err != nil {
+			return state, snap, nil, err
+		}
+	}
+	if box, ok := p.data[snapshotKey]; ok {
+		if err := json.Unmarshal(box.Content.V, &snap); err != nil {
+			return state, snap, nil, err
+		}
+	}
+	keys := make([]string, 0, len(p.data))
+	for k := range p.data {
+		if strings.HasPrefix(k, entryPrefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	entries := make([]entry, 0, len(keys))
+	for _, k := range keys {
+		var e entry
+		if err := json.Unmarshal(p.data[k].Content.V, &e); err != nil {
+			return state, snap, nil, err
+		}
+		if e.Index > snap.Index {
+			entries = append(entries, e)
+		}
+	}
+	return state, snap, entries, nil
+}
+
+func entryKey(index uint64) string {
+	return fmt.Sprintf("%s%020d", entryPrefix, index)
+}
+
+// set returns the change storing the value as JSON.
+func set(key string, value interface{}) (kv.Change, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return kv.Change{}, err
+	}
+	return kv.Change{Kind: kv.ChangeSet, Key: key, Box: kv.TtlBox{Content: kv.T{V: data, ContentType: "application/json"}}}, nil
+}
+
+// write makes the changes durable before returning.
+func (p *persister) write(changes ...kv.Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	for _, ch := range changes {
+		if ch.Kind == kv.ChangeDelete {
+			delete(p.data, ch.Key)
+		} else {
+			p.data[ch.Key] = ch.Box
+		}
+	}
+	if p.log == nil {
+		return nil
+	}
+	if err := p.log.Append(changes...); err != nil {
+		return err
+	}
+	if err := p.log.Sync(); err != nil {
+		return err
+	}
+	p.appended += len(changes)
+	if p.appended < compactAfter {
+		return nil
+	}
+	if err := p.log.Rotate(); err != nil {
+		return err
+	}
+	if err := p.log.Save(p.copy()); err != nil {
+		return err
+	}
+	p.appended = 0
+	return nil
+}
+
+func (p *persister) copy() map[string]kv.TtlBox {
+	data := make(map[string]kv.TtlBox, len(p.data))
+	for k, v := range p.data {
+		data[k] = v
+	}
+	return data
+}
@@ -0,0 +1,81 @@
+package raft
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"kv-ttl/pb"
+	"sync"
+)
+
+// Transport delivers the calls of a node to the other members by their addresses.
+type Transport interface {
+	RequestVote(ctx context.Context, address string, req *pb.VoteRequest) (*pb.VoteResponse, error)
+	AppendEntries(ctx context.Context, address string, req *pb.AppendRequest) (*pb.AppendResponse, error)
+	InstallSnapshot(ctx context.Context, address string, req *pb.SnapshotRequest) (*pb.SnapshotResponse, error)
+}
+
+// GrpcTransport calls the Cluster service of the members keeping a connection to each of them.
+type GrpcTransport struct {
+	opts []grpc.DialOption
+
+	mu    sync.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+// NewGrpcTransport creates the transport dialing the members with the options.
+func NewGrpcTransport(opts ...grpc.DialOption) *GrpcTransport {
+	return &GrpcTransport{opts: opts, conns: make(map[string]*grpc.ClientConn)}
+}
+
+func (t *GrpcTransport) client(address string) (pb.ClusterClient, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	conn, ok := t.conns[address]
+	if !ok {
+		var err error
+		conn, err = grpc.Dial(address, t.opts...)
+		if err != nil {
+			return nil, err
+		}
+		t.conns[address] = conn
+	}
+	return pb.NewClusterClient(conn), nil
+}
+
+func (t *GrpcTransport) RequestVote(ctx context.Context, address string, req *pb.VoteRequest) (*pb.VoteResponse, error) {
+	c, err := t.client(address)
+	if err != nil {
+		return nil, err
+	}
+	return c.RequestVote(ctx, req)
+}
+
+func (t *GrpcTransport) AppendEntries(ctx context.Context, address string, req *pb.AppendRequest) (*pb.AppendResponse, error) {
+	c, err := t.client(address)
+	if err != nil {
+		return nil, err
+	}
+	return c.AppendEntries(ctx, req)
+}
+
+func (t *GrpcTransport) InstallSnapshot(ctx context.Context, address string, req *pb.SnapshotRequest) (*pb.SnapshotResponse, error) {
+	c, err := t.client(address)
+	if err != nil {
+		return nil, err
+	}
+	return c.InstallSnapshot(ctx, req)
+}
+
+// Close closes the connections to the members.
+func (t *GrpcTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var err error
+	for address, conn := range t.conns {
+		if cErr := conn.Close(); err == nil {
+			err = cErr
+		}
+		delete(t.conns, address)
+	}
+	return err
+}
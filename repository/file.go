@@ -30,6 +30,26 @@ func (r *FileRepo) RestoreInto(m *map[string]kv.TtlBox) error {
 	return json.NewDecoder(f).Decode(&m)
 }
 
+// Namespace returns the storage of the namespace kept in a file next to the default one.
+func (r *FileRepo) Namespace(name string) kv.Storage {
+	return NewFileRepo(namespaceFile(r.fileName, name))
+}
+
+// RestoreNamespaces reads the options of the namespaces saved before.
+func (r *FileRepo) RestoreNamespaces() (map[string]kv.NamespaceOptions, error) {
+	return restoreNamespaces(r.fileName)
+}
+
+// SaveNamespaces replaces the saved namespaces.
+func (r *FileRepo) SaveNamespaces(m map[string]kv.NamespaceOptions) error {
+	return saveNamespaces(r.fileName, m)
+}
+
+// DropNamespace deletes the file of the namespace.
+func (r *FileRepo) DropNamespace(name string) error {
+	return removeFile(namespaceFile(r.fileName, name))
+}
+
 // Save create a file if needed and dumps json representation of the map into it.
 func (r *FileRepo) Save(m map[string]kv.TtlBox) error {
 	f, err := os.OpenFile(r.fileName, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fileMode)
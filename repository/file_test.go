@@ -1,10 +1,13 @@
 package repository
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"kv-ttl/kv"
 	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"sort"
@@ -13,7 +16,7 @@ import (
 )
 
 // Removes all the previously created *.json files from the current directory.
-// Initiate a cache with backing up to file. Waits for scheduled backup happened.
+// Initiate a cache with backing up to file. Advances the clock until the scheduled backup happened.
 // Creates another cache instance based on the same file.
 // Sorts values received from the new cache and tests against the original data.
 func TestCacheBackup(t *testing.T) {
@@ -27,29 +30,167 @@ func TestCacheBackup(t *testing.T) {
 	}
 	// init
 	fileStorage := NewFileRepo("snap.json")
+	clock := kv.NewFakeClock(time.Now())
 	config := kv.Configuration{
 		BackupInterval: 1 * time.Second,
 		Storage:        fileStorage,
+		Clock:          clock,
 	}
 	// insert
 	cache := kv.NewCache(config)
 	values := []kv.T{
-		{V: "one"},
-		{V: "two"},
-		{V: "three"},
+		{V: []byte("one")},
+		{V: []byte("two")},
+		{V: []byte("three")},
 	}
 	for i, v := range values {
 		cache.Add(fmt.Sprintf("%d", i), v)
 	}
 	// verify
-	time.Sleep(1200 * time.Millisecond)
+	clock.Advance(1200 * time.Millisecond)
 	newCache := kv.NewCache(kv.Configuration{Storage: fileStorage})
 	storedValues := newCache.ListAll()
 
-	sort.Slice(values, func(i, j int) bool { return values[i].V > values[j].V })
-	sort.Slice(storedValues, func(i, j int) bool { return storedValues[i].V > storedValues[j].V })
+	sort.Slice(values, func(i, j int) bool { return bytes.Compare(values[i].V, values[j].V) > 0 })
+	sort.Slice(storedValues, func(i, j int) bool { return bytes.Compare(storedValues[i].V, storedValues[j].V) > 0 })
 
 	if !reflect.DeepEqual(values, storedValues) {
 		t.Errorf("%v\n!=\n%v", values, storedValues)
 	}
 }
+
+// Initiates a cache without scheduled backups, closes it and verifies
+// that the final snapshot was written to the file.
+func TestCacheCloseSavesSnapshot(t *testing.T) {
+	fileStorage := NewFileRepo("close.json")
+	defer os.Remove("close.json")
+	cache := kv.NewCache(kv.Configuration{Storage: fileStorage})
+	cache.Add("key", kv.T{V: []byte("value")})
+	if err := cache.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	newCache := kv.NewCache(kv.Configuration{Storage: fileStorage})
+	defer newCache.Close(context.Background())
+	v, ok := newCache.Value("key")
+	if !ok || string(v.V) != "value" {
+		t.Errorf("expected restored value, got %v %v", v, ok)
+	}
+}
+
+// Versions must survive a restart, so the restored cache keeps assigning greater ones.
+func TestVersionRestored(t *testing.T) {
+	fileStorage := NewFileRepo("version.json")
+	defer os.Remove("version.json")
+	cache := kv.NewCache(kv.Configuration{Storage: fileStorage})
+	cache.Add("key", kv.T{V: []byte("value")})
+	saved, _ := cache.Entry("key")
+	if err := cache.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	newCache := kv.NewCache(kv.Configuration{Storage: fileStorage})
+	defer newCache.Close(context.Background())
+	restored, ok := newCache.Entry("key")
+	if !ok || restored.Version != saved.Version {
+		t.Errorf("expected version %d, got %v %v", saved.Version, restored, ok)
+	}
+	newCache.Add("other", kv.T{V: []byte("value")})
+	if other, _ := newCache.Entry("other"); other.Version <= saved.Version {
+		t.Errorf("expected version greater than %d, got %d", saved.Version, other.Version)
+	}
+}
+
+// Releases a lock and restarts the cache, the next acquisition must get a greater token
+// although the entry that had the previous one is gone. The wal cache without close
+// is restored from the log only.
+func TestLockTokenAfterRestart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "version")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	tests := []struct {
+		name    string
+		storage func(name string) kv.Storage
+		close   bool
+	}{
+		{"file", func(name string) kv.Storage { return NewFileRepo(name) }, true},
+		{"wal", func(name string) kv.Storage { return NewWalRepo(name) }, true},
+		{"wal log", func(name string) kv.Storage { return NewWalRepo(name) }, false},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(dir, fmt.Sprintf("cache%d.json", i))
+			cache := kv.NewCache(kv.Configuration{Storage: tt.storage(name)})
+			token, err := cache.Lock("job", "a", time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := cache.Unlock("job", "a", token); err != nil {
+				t.Fatal(err)
+			}
+			if tt.close {
+				if err := cache.Close(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			restarted := kv.NewCache(kv.Configuration{Storage: tt.storage(name)})
+			defer restarted.Close(context.Background())
+			next, err := restarted.Lock("job", "b", time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next <= token {
+				t.Errorf("expected a token greater than %d, got %d", token, next)
+			}
+			if n := len(restarted.ListAll()); n != 1 {
+				t.Errorf("expected only the lock, got %d entries", n)
+			}
+		})
+	}
+}
+
+// Snapshots written before values became binary keep plain strings and must still be restored.
+func TestLegacySnapshot(t *testing.T) {
+	legacy := `{"key":{"CreatedAt":"2020-06-12T16:51:14Z","Expired":null,"Content":{"V":"old value"}}}`
+	if err := ioutil.WriteFile("legacy.json", []byte(legacy), fileMode); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove("legacy.json")
+	fileStorage := NewFileRepo("legacy.json")
+	cache := kv.NewCache(kv.Configuration{Storage: fileStorage})
+	if v, ok := cache.Value("key"); !ok || string(v.V) != "old value" {
+		t.Errorf("expected legacy value, got %v %v", v, ok)
+	}
+
+	cache.Add("binary", kv.T{V: []byte{0xff, 0x00, 0xfe}, ContentType: "application/octet-stream"})
+	if err := cache.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	newCache := kv.NewCache(kv.Configuration{Storage: fileStorage})
+	defer newCache.Close(context.Background())
+	v, _ := newCache.Value("binary")
+	if !bytes.Equal(v.V, []byte{0xff, 0x00, 0xfe}) || v.ContentType != "application/octet-stream" {
+		t.Errorf("expected binary value to be restored, got %v", v)
+	}
+}
+
+// The sliding TTL of an entry must survive a restart.
+func TestSlidingTtlRestored(t *testing.T) {
+	fileStorage := NewFileRepo("sliding.json")
+	defer os.Remove("sliding.json")
+	cache := kv.NewCache(kv.Configuration{Storage: fileStorage})
+	cache.AddWithSlidingTtl("session", kv.T{V: []byte("data")}, time.Hour)
+	if err := cache.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	newCache := kv.NewCache(kv.Configuration{Storage: fileStorage})
+	defer newCache.Close(context.Background())
+	box, ok := newCache.Entry("session")
+	if !ok || box.Sliding != time.Hour {
+		t.Errorf("expected sliding ttl to be restored, got %v %v", box.Sliding, ok)
+	}
+}
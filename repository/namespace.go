@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"encoding/json"
+	"kv-ttl/kv"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// namespaceFile returns the name of the file keeping the namespace next to
+// the file of the default one, e.g. "snap.ns-team.json" for "snap.json".
+func namespaceFile(fileName, name string) string {
+	ext := filepath.Ext(fileName)
+	return strings.TrimSuffix(fileName, ext) + ".ns-" + name + ext
+}
+
+// catalogFile returns the name of the file keeping the options of the namespaces,
+// e.g. "snap.namespaces.json" for "snap.json".
+func catalogFile(fileName string) string {
+	ext := filepath.Ext(fileName)
+	return strings.TrimSuffix(fileName, ext) + ".namespaces" + ext
+}
+
+// restoreNamespaces reads the catalog file, a missing file means there are no namespaces.
+func restoreNamespaces(fileName string) (map[string]kv.NamespaceOptions, error) {
+	m := make(map[string]kv.NamespaceOptions)
+	f, err := os.Open(catalogFile(fileName))
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return m, json.NewDecoder(f).Decode(&m)
+}
+
+// saveNamespaces atomically replaces the catalog file.
+func saveNamespaces(fileName string, m map[string]kv.NamespaceOptions) error {
+	return writeJSON(catalogFile(fileName), m)
+}
+
+// writeJSON atomically replaces the file with the json representation of v.
+func writeJSON(fileName string, v interface{}) error {
+	tmp := fileName + tmpSuffix
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fileMode)
+	if err != nil {
+		return err
+	}
+	if err = json.NewEncoder(f).Encode(v); err == nil {
+		err = f.Sync()
+	}
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, fileName)
+}
+
+// removeFile deletes the file if it exists.
+func removeFile(fileName string) error {
+	if err := os.Remove(fileName); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
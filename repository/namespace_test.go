@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"io/ioutil"
+	"kv-ttl/kv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Namespaces are kept in separate files and restored with their options on start.
+func TestNamespacesRestored(t *testing.T) {
+	for _, storage := range []struct {
+		name string
+		new  func(fileName string) kv.Storage
+	}{
+		{"file", func(fileName string) kv.Storage { return NewFileRepo(fileName) }},
+		{"wal", func(fileName string) kv.Storage { return NewWalRepo(fileName) }},
+	} {
+		t.Run(storage.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "namespaces")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			fileName := filepath.Join(dir, "snap.json")
+
+			config := kv.Configuration{Storage: storage.new(fileName)}
+			n := kv.NewNamespaces(kv.NewCache(config), config)
+			if err := n.Create("team", kv.NamespaceOptions{DefaultTtl: time.Hour}); err != nil {
+				t.Fatal(err)
+			}
+			if err := n.Create("tmp", kv.NamespaceOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			def, _ := n.Cache(kv.DefaultNamespace)
+			team, _ := n.Cache("team")
+			tmp, _ := n.Cache("tmp")
+			def.Add("key", kv.T{V: []byte("default")})
+			team.Add("key", kv.T{V: []byte("team")})
+			tmp.Add("key", kv.T{V: []byte("tmp")})
+			if err := n.Drop(context.Background(), "tmp"); err != nil {
+				t.Fatal(err)
+			}
+			if err := n.Close(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			config = kv.Configuration{Storage: storage.new(fileName)}
+			n = kv.NewNamespaces(kv.NewCache(config), config)
+			defer n.Close(context.Background())
+			infos := n.List()
+			if len(infos) != 2 || infos[1].Name != "team" || infos[1].DefaultTtl != time.Hour {
+				t.Fatalf("unexpected namespaces %v", infos)
+			}
+			def, _ = n.Cache(kv.DefaultNamespace)
+			team, _ = n.Cache("team")
+			if v, _ := def.Value("key"); string(v.V) != "default" {
+				t.Errorf("expected default value, got %s", v.V)
+			}
+			if v, _ := team.Value("key"); string(v.V) != "team" {
+				t.Errorf("expected team value, got %s", v.V)
+			}
+			files, _ := filepath.Glob(filepath.Join(dir, "snap.ns-tmp*"))
+			if len(files) != 0 {
+				t.Errorf("expected files of the dropped namespace to be removed, got %v", files)
+			}
+		})
+	}
+}
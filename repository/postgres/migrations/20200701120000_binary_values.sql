@@ -0,0 +1,12 @@
+-- +goose Up
+alter table cache_snapshot add column value bytea;
+update cache_snapshot
+set value = convert_to(json_value -> 'Content' ->> 'V', 'UTF8'),
+    json_value = json_value #- '{Content,V}'
+where json_value -> 'Content' ? 'V';
+
+-- +goose Down
+update cache_snapshot
+set json_value = jsonb_set(json_value, '{Content,V}', to_jsonb(convert_from(value, 'UTF8')))
+where value is not null;
+alter table cache_snapshot drop column value;
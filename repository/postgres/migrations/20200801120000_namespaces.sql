@@ -0,0 +1,15 @@
+-- +goose Up
+alter table cache_snapshot add column namespace text not null default 'default';
+alter table cache_snapshot drop constraint cache_snapshot_pkey;
+alter table cache_snapshot add primary key (namespace, id);
+create table cache_namespace (
+    name text primary key,
+    options jsonb not null
+);
+
+-- +goose Down
+drop table cache_namespace;
+delete from cache_snapshot where namespace <> 'default';
+alter table cache_snapshot drop constraint cache_snapshot_pkey;
+alter table cache_snapshot add primary key (id);
+alter table cache_snapshot drop column namespace;
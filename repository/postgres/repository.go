@@ -5,56 +5,65 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"github.com/lib/pq"
 	"kv-ttl/kv"
 	"log"
 )
 
-// Repository implements the kv.Storage interface and provides storing cache values
+// Repository implements the kv.IncrementalStorage interface and provides storing cache values
 // in a Postgres table. Each key-value pair mapped to a row in the table. The key is
-// used as a PK, the value is stored as a JSONB type.
+// used as a PK along with the namespace, the payload is stored as a BYTEA type and the rest
+// of the entry as a JSONB type. The repository keeps the default namespace, the others are
+// kept by the repositories returned by Namespace.
 type Repository struct {
-	db *sql.DB
+	db        *sql.DB
+	namespace string
 }
 
 func NewRepository(db *sql.DB) *Repository {
-	return &Repository{db: db}
+	return &Repository{db: db, namespace: kv.DefaultNamespace}
 }
 
 // RestoreInto reads rows from the database table and populates the given map.
 func (p *Repository) RestoreInto(m *map[string]kv.TtlBox) error {
-	rows, err := p.db.Query(`select id, json_value from cache_snapshot`)
+	rows, err := p.db.Query(`select id, json_value, value from cache_snapshot where namespace = $1`, p.namespace)
 	if err != nil {
 		return err
 	}
-	var (
-		key   string
-		value jsonValue
-	)
+	defer rows.Close()
 	for rows.Next() {
-		if err = rows.Scan(&key, &value); err != nil {
+		var (
+			key     string
+			value   jsonValue
+			payload []byte
+		)
+		if err = rows.Scan(&key, &value, &payload); err != nil {
 			continue
 		}
 		box := kv.TtlBox(value)
+		if payload != nil {
+			box.Content.V = payload
+		}
 		(*m)[key] = box
 	}
-	return nil
+	return rows.Err()
 }
 
 // Save deletes all the values from the database table and then inserts the new values.
 func (p *Repository) Save(m map[string]kv.TtlBox) error {
-	_, err := p.db.Exec(`delete from cache_snapshot where true`)
+	_, err := p.db.Exec(`delete from cache_snapshot where namespace = $1`, p.namespace)
 	if err != nil {
 		return err
 	}
 	if len(m) == 0 {
 		return nil
 	}
-	stmt, err := p.db.Prepare(`insert into cache_snapshot values ($1, $2)`)
+	stmt, err := p.db.Prepare(`insert into cache_snapshot (namespace, id, json_value, value) values ($1, $2, $3, $4)`)
 	if err != nil {
 		return err
 	}
 	for k, v := range m {
-		_, err = stmt.Exec(k, jsonValue(v))
+		_, err = stmt.Exec(p.namespace, k, jsonValue(v), v.Content.V)
 		if err != nil {
 			log.Println(err)
 		}
@@ -62,6 +71,111 @@ func (p *Repository) Save(m map[string]kv.TtlBox) error {
 	return nil
 }
 
+// SaveChanges upserts the updated values and deletes the removed keys inside a single transaction.
+func (p *Repository) SaveChanges(updated map[string]kv.TtlBox, deleted []string) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	txCompleted := false
+	defer func() {
+		if !txCompleted {
+			_ = tx.Rollback()
+		}
+	}()
+	if len(deleted) > 0 {
+		_, err = tx.Exec(`delete from cache_snapshot where namespace = $1 and id = any($2)`, p.namespace, pq.Array(deleted))
+		if err != nil {
+			return err
+		}
+	}
+	if len(updated) > 0 {
+		stmt, err := tx.Prepare(`insert into cache_snapshot (namespace, id, json_value, value) values ($1, $2, $3, $4)
+			on conflict (namespace, id) do update set json_value = excluded.json_value, value = excluded.value`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		for k, v := range updated {
+			if _, err = stmt.Exec(p.namespace, k, jsonValue(v), v.Content.V); err != nil {
+				return err
+			}
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	txCompleted = true
+	return nil
+}
+
+// Namespace returns the repository of the namespace sharing the same table.
+func (p *Repository) Namespace(name string) kv.Storage {
+	return &Repository{db: p.db, namespace: name}
+}
+
+// RestoreNamespaces reads the options of the namespaces saved before.
+func (p *Repository) RestoreNamespaces() (map[string]kv.NamespaceOptions, error) {
+	rows, err := p.db.Query(`select name, options from cache_namespace`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	m := make(map[string]kv.NamespaceOptions)
+	for rows.Next() {
+		var (
+			name string
+			opts []byte
+		)
+		if err = rows.Scan(&name, &opts); err != nil {
+			return nil, err
+		}
+		var o kv.NamespaceOptions
+		if err = json.Unmarshal(opts, &o); err != nil {
+			return nil, err
+		}
+		m[name] = o
+	}
+	return m, rows.Err()
+}
+
+// SaveNamespaces replaces the saved namespaces inside a single transaction.
+func (p *Repository) SaveNamespaces(m map[string]kv.NamespaceOptions) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	txCompleted := false
+	defer func() {
+		if !txCompleted {
+			_ = tx.Rollback()
+		}
+	}()
+	if _, err = tx.Exec(`delete from cache_namespace where true`); err != nil {
+		return err
+	}
+	for name, o := range m {
+		opts, err := json.Marshal(o)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.Exec(`insert into cache_namespace (name, options) values ($1, $2)`, name, opts); err != nil {
+			return err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	txCompleted = true
+	return nil
+}
+
+// DropNamespace deletes the values of the namespace.
+func (p *Repository) DropNamespace(name string) error {
+	_, err := p.db.Exec(`delete from cache_snapshot where namespace = $1`, name)
+	return err
+}
+
 // For reasons that I don't know the code below fails to execute the copy statement
 // with an error: "unexpected message type 0x51 during COPY from stdin".
 // Origins are taken from https://godoc.org/github.com/lib/pq#hdr-Bulk_imports.
@@ -91,7 +205,7 @@ func (p *Repository) Save(m map[string]kv.TtlBox) error {
 //		return err
 //	}
 //	for k, v := range m {
-//		_, err = stmt.Exec(k, jsonValue(v))
+//		_, err = stmt.Exec(k, jsonValue(v), v.Content.V)
 //		if err != nil {
 //			return err
 //		}
@@ -104,11 +218,12 @@ func (p *Repository) Save(m map[string]kv.TtlBox) error {
 //}
 
 // Helper structure used to serialize into and deserialize original values
-// from Postgres JSONB type.
+// from Postgres JSONB type. The payload is stored in a separate column.
 type jsonValue kv.TtlBox
 
 // Values makes the jsonValue type implement the driver.Valuer interface.
 func (v jsonValue) Value() (driver.Value, error) {
+	v.Content.V = nil
 	return json.Marshal(v)
 }
 
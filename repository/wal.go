@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"kv-ttl/kv"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	walSuffix = ".wal."
+	tmpSuffix = ".tmp"
+)
+
+// WalRepo implements the kv.LogStorage interface. The cache data is stored as
+// a json snapshot file plus a sequence of append-only log segments next to it.
+// Every change of the cache is written to the current segment as a json line.
+// A successful Save replaces the snapshot and deletes the segments it covers.
+type WalRepo struct {
+	fileName string
+
+	mu      sync.Mutex
+	segment *os.File
+	enc     *json.Encoder
+	seq     int
+	covered int
+}
+
+// walRecord is the on-disk representation of a kv.Change. Changes appended
+// together are written as a single batch record, so they are replayed all or none.
+type walRecord struct {
+	Op    string      `json:"op"`
+	Key   string      `json:"key,omitempty"`
+	Box   *kv.TtlBox  `json:"box,omitempty"`
+	Batch []walRecord `json:"batch,omitempty"`
+}
+
+const (
+	opSet    = "set"
+	opDelete = "del"
+	opBatch  = "batch"
+)
+
+func NewWalRepo(fileName string) *WalRepo {
+	return &WalRepo{
+		fileName: fileName,
+	}
+}
+
+// RestoreInto reads the last snapshot if it exists and replays the log segments on top of it.
+func (r *WalRepo) RestoreInto(m *map[string]kv.TtlBox) error {
+	f, err := os.Open(r.fileName)
+	switch {
+	case err == nil:
+		err = json.NewDecoder(f).Decode(m)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	case !os.IsNotExist(err):
+		return err
+	}
+
+	seqs, err := r.segments()
+	if err != nil {
+		return err
+	}
+	for _, seq := range seqs {
+		if err := r.replay(r.segmentName(seq), *m); err != nil {
+			return err
+		}
+	}
+	r.mu.Lock()
+	if len(seqs) > 0 && seqs[len(seqs)-1] > r.seq {
+		r.seq = seqs[len(seqs)-1]
+	}
+	r.mu.Unlock()
+	return nil
+}
+
+// replay applies records of a single segment to the map. A broken record
+// at the end of a segment is the result of an interrupted write, so
+// the rest of the segment is skipped.
+func (r *WalRepo) replay(name string, m map[string]kv.TtlBox) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	for {
+		var rec walRecord
+		err := dec.Decode(&rec)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			log.Printf("wal: skipped the rest of %s: %v\n", name, err)
+			return nil
+		}
+		apply(rec, m)
+	}
+}
+
+func apply(rec walRecord, m map[string]kv.TtlBox) {
+	switch rec.Op {
+	case opSet:
+		if rec.Box != nil {
+			m[rec.Key] = *rec.Box
+		}
+	case opDelete:
+		delete(m, rec.Key)
+	case opBatch:
+		for _, r := range rec.Batch {
+			apply(r, m)
+		}
+	}
+}
+
+// Append writes the changes to the current log segment, opening a new one if needed.
+// A failed write may leave a broken record that stops the replay of the segment,
+// so the segment is closed and the following changes go to a new one.
+func (r *WalRepo) Append(changes ...kv.Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.segment == nil {
+		if err := r.openSegment(); err != nil {
+			return err
+		}
+	}
+	rec := toRecord(changes[0])
+	if len(changes) > 1 {
+		rec = walRecord{Op: opBatch, Batch: make([]walRecord, len(changes))}
+		for i, ch := range changes {
+			rec.Batch[i] = toRecord(ch)
+		}
+	}
+	if err := r.enc.Encode(rec); err != nil {
+		if cErr := r.closeSegment(); cErr != nil {
+			log.Printf("wal: closing %s after a failed write: %v\n", r.segmentName(r.seq), cErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func toRecord(ch kv.Change) walRecord {
+	if ch.Kind == kv.ChangeDelete {
+		return walRecord{Op: opDelete, Key: ch.Key}
+	}
+	return walRecord{Op: opSet, Key: ch.Key, Box: &ch.Box}
+}
+
+// Sync flushes the current log segment to disk.
+func (r *WalRepo) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.segment == nil {
+		return nil
+	}
+	return r.segment.Sync()
+}
+
+// Rotate closes the current log segment. The next change goes to a new segment,
+// and the closed ones are deleted by the next successful Save.
+func (r *WalRepo) Rotate() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.covered = r.seq
+	return r.closeSegment()
+}
+
+// Save atomically replaces the snapshot file and removes the log segments
+// written before the last rotation.
+func (r *WalRepo) Save(m map[string]kv.TtlBox) error {
+	if err := writeJSON(r.fileName, m); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	covered := r.covered
+	r.mu.Unlock()
+	seqs, err := r.segments()
+	if err != nil {
+		return err
+	}
+	for _, seq := range seqs {
+		if seq > covered {
+			break
+		}
+		if err := os.Remove(r.segmentName(seq)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Namespace returns the storage of the namespace kept in the files next to the default one.
+func (r *WalRepo) Namespace(name string) kv.Storage {
+	return NewWalRepo(namespaceFile(r.fileName, name))
+}
+
+// RestoreNamespaces reads the options of the namespaces saved before.
+func (r *WalRepo) RestoreNamespaces() (map[string]kv.NamespaceOptions, error) {
+	return restoreNamespaces(r.fileName)
+}
+
+// SaveNamespaces replaces the saved namespaces.
+func (r *WalRepo) SaveNamespaces(m map[string]kv.NamespaceOptions) error {
+	return saveNamespaces(r.fileName, m)
+}
+
+// DropNamespace deletes the snapshot and the log segments of the namespace.
+func (r *WalRepo) DropNamespace(name string) error {
+	ns := NewWalRepo(namespaceFile(r.fileName, name))
+	seqs, err := ns.segments()
+	if err != nil {
+		return err
+	}
+	for _, seq := range seqs {
+		if err := removeFile(ns.segmentName(seq)); err != nil {
+			return err
+		}
+	}
+	return removeFile(ns.fileName)
+}
+
+// Close flushes and closes the current log segment.
+func (r *WalRepo) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.closeSegment()
+}
+
+func (r *WalRepo) openSegment() error {
+	f, err := os.OpenFile(r.segmentName(r.seq+1), os.O_WRONLY|os.O_CREATE|os.O_APPEND, fileMode)
+	if err != nil {
+		return err
+	}
+	r.seq++
+	r.segment = f
+	r.enc = json.NewEncoder(f)
+	return nil
+}
+
+func (r *WalRepo) closeSegment() error {
+	if r.segment == nil {
+		return nil
+	}
+	err := r.segment.Sync()
+	if cErr := r.segment.Close(); err == nil {
+		err = cErr
+	}
+	r.segment = nil
+	r.enc = nil
+	return err
+}
+
+func (r *WalRepo) segmentName(seq int) string {
+	return fmt.Sprintf("%s%s%d", r.fileName, walSuffix, seq)
+}
+
+// segments returns sequence numbers of the existing log segments in ascending order.
+func (r *WalRepo) segments() ([]int, error) {
+	names, err := filepath.Glob(r.fileName + walSuffix + "*")
+	if err != nil {
+		return nil, err
+	}
+	seqs := make([]int, 0, len(names))
+	for _, name := range names {
+		seq, err := strconv.Atoi(strings.TrimPrefix(name, r.fileName+walSuffix))
+		if err != nil {
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	sort.Ints(seqs)
+	return seqs, nil
+}
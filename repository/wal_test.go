@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"kv-ttl/kv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Changes the cache without taking any snapshot and abandons it as if the process
+// crashed. A new cache over the same files must see every change replayed from the log.
+// Then closes the cache and verifies that the log was compacted into the snapshot.
+func TestWalReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "cache.json")
+
+	cache := kv.NewCache(kv.Configuration{Storage: NewWalRepo(name)})
+	cache.Add("1", kv.T{V: []byte("one")})
+	cache.Add("2", kv.T{V: []byte("two")})
+	cache.AddWithTtl("3", kv.T{V: []byte("three")}, time.Hour)
+	cache.Remove("2")
+	stamp := time.Now().Add(time.Minute)
+	cache.SetTtl("1", &stamp)
+
+	restored := kv.NewCache(kv.Configuration{Storage: NewWalRepo(name)})
+	if v, ok := restored.Value("1"); !ok || string(v.V) != "one" {
+		t.Errorf("expected #1 to be restored, got %v %v", v, ok)
+	}
+	if _, ok := restored.Value("2"); ok {
+		t.Error("expected #2 to be removed")
+	}
+	if v, ok := restored.Value("3"); !ok || string(v.V) != "three" {
+		t.Errorf("expected #3 to be restored, got %v %v", v, ok)
+	}
+	if err := restored.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	segments, _ := filepath.Glob(name + walSuffix + "*")
+	if len(segments) != 0 {
+		t.Errorf("expected log to be compacted, found %v", segments)
+	}
+	final := kv.NewCache(kv.Configuration{Storage: NewWalRepo(name)})
+	defer final.Close(context.Background())
+	if all := final.ListAll(); len(all) != 2 {
+		t.Errorf("expected 2 values after compaction, got %v", all)
+	}
+}
+
+// A transaction is logged as a single record: it is replayed completely,
+// and a torn record of a transaction is not replayed at all.
+func TestWalTransaction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "cache.json")
+
+	cache := kv.NewCache(kv.Configuration{Storage: NewWalRepo(name)})
+	cache.Add("user:1", kv.T{V: []byte("john")})
+	err = cache.Update(func(tx kv.Txn) error {
+		tx.Set("user:1", kv.T{V: []byte("jack")}, 0)
+		tx.Delete("name:john")
+		tx.Set("name:jack", kv.T{V: []byte("1")}, 0)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := kv.NewCache(kv.Configuration{Storage: NewWalRepo(name)})
+	if v, _ := restored.Value("user:1"); string(v.V) != "jack" {
+		t.Errorf("expected the transaction to be replayed, got %s", v.V)
+	}
+	if _, ok := restored.Value("name:jack"); !ok {
+		t.Error("expected the index key to be replayed")
+	}
+
+	segments, _ := filepath.Glob(name + walSuffix + "*")
+	data, err := ioutil.ReadFile(segments[len(segments)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(segments[len(segments)-1], data[:len(data)-10], fileMode); err != nil {
+		t.Fatal(err)
+	}
+	torn := kv.NewCache(kv.Configuration{Storage: NewWalRepo(name)})
+	if v, _ := torn.Value("user:1"); string(v.V) != "john" {
+		t.Errorf("expected the torn transaction to be skipped, got %s", v.V)
+	}
+	if _, ok := torn.Value("name:jack"); ok {
+		t.Error("expected no part of the torn transaction to be replayed")
+	}
+}
+
+// A failed write leaves a broken record at the end of the segment. The changes
+// written after it go to a new segment, so the replay doesn't stop before them.
+func TestWalFailedAppend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "cache.json")
+
+	repo := NewWalRepo(name)
+	cache := kv.NewCache(kv.Configuration{Storage: repo})
+	if err := cache.Add("1", kv.T{V: []byte("one")}); err != nil {
+		t.Fatal(err)
+	}
+	// The write of the broken record fails as if the disk was full.
+	f, err := os.OpenFile(repo.segmentName(repo.seq), os.O_WRONLY|os.O_APPEND, fileMode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"op":"set","key":"bro`)
+	f.Close()
+	repo.segment.Close()
+	if err := cache.Add("broken", kv.T{V: []byte("x")}); !errors.Is(err, kv.ErrUnavailable) {
+		t.Fatalf("expected the write to fail, got %v", err)
+	}
+	if err := cache.Add("2", kv.T{V: []byte("two")}); err != nil {
+		t.Fatalf("expected the write to a new segment, got %v", err)
+	}
+
+	restored := kv.NewCache(kv.Configuration{Storage: NewWalRepo(name)})
+	defer restored.Close(context.Background())
+	for _, k := range []string{"1", "2"} {
+		if _, ok := restored.Value(k); !ok {
+			t.Errorf("expected %s to be replayed", k)
+		}
+	}
+	if _, ok := restored.Value("broken"); ok {
+		t.Error("expected the failed write not to be replayed")
+	}
+}
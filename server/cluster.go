@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"kv-ttl/kv"
+	"kv-ttl/pb"
+	"kv-ttl/raft"
+)
+
+// clusterServer implements ClusterServer interface for a node of the consensus mode.
+type clusterServer struct {
+	node *raft.Node
+}
+
+// NewClusterServer serves the calls of the other nodes and the admin calls to the node.
+// The cache of the node is served by NewReplicaServer.
+func NewClusterServer(node *raft.Node) pb.ClusterServer {
+	return &clusterServer{node: node}
+}
+
+func (c *clusterServer) RequestVote(ctx context.Context, req *pb.VoteRequest) (*pb.VoteResponse, error) {
+	return c.node.RequestVote(req), nil
+}
+
+func (c *clusterServer) AppendEntries(ctx context.Context, req *pb.AppendRequest) (*pb.AppendResponse, error) {
+	return c.node.AppendEntries(req), nil
+}
+
+func (c *clusterServer) InstallSnapshot(ctx context.Context, req *pb.SnapshotRequest) (*pb.SnapshotResponse, error) {
+	return c.node.InstallSnapshot(req), nil
+}
+
+func (c *clusterServer) AddMember(ctx context.Context, req *pb.Member) (*pb.Empty, error) {
+	if req.Id == "" || req.Address == "" {
+		return nil, status.Error(codes.InvalidArgument, "member id and address are required")
+	}
+	if err := c.node.AddMember(raft.Member{ID: req.Id, Address: req.Address}); err != nil {
+		return nil, c.memberError(err)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (c *clusterServer) RemoveMember(ctx context.Context, req *pb.Member) (*pb.Empty, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "member id is required")
+	}
+	if err := c.node.RemoveMember(req.Id); err != nil {
+		return nil, c.memberError(err)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (c *clusterServer) Members(ctx context.Context, req *pb.Empty) (*pb.MemberList, error) {
+	members, leader := c.node.Members()
+	resp := &pb.MemberList{Members: make([]*pb.Member, len(members)), Leader: leader}
+	for i, m := range members {
+		resp.Members[i] = &pb.Member{Id: m.ID, Address: m.Address}
+	}
+	return resp, nil
+}
+
+// memberError converts the error of a membership change into a gRPC status error.
+func (c *clusterServer) memberError(err error) error {
+	switch {
+	case errors.Is(err, kv.ErrReadOnly):
+		return readOnlyError(c.node.Leader())
+	case errors.Is(err, raft.ErrMembershipChange):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, raft.ErrLeadershipLost), errors.Is(err, raft.ErrStopped):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"kv-ttl/kv"
+	"kv-ttl/pb"
+	"kv-ttl/raft"
+	"net"
+	"testing"
+	"time"
+)
+
+type testNode struct {
+	node    *raft.Node
+	addr    string
+	storage pb.StorageClient
+	cluster pb.ClusterClient
+	stop    func()
+}
+
+// startNode serves a cluster node on localhost.
+func startNode(t *testing.T, id string, bootstrap bool) *testNode {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	transport := raft.NewGrpcTransport(grpc.WithInsecure())
+	node, err := raft.NewNode(raft.Config{
+		ID:                id,
+		Address:           addr,
+		Transport:         transport,
+		Bootstrap:         bootstrap,
+		ElectionTimeout:   150 * time.Millisecond,
+		HeartbeatInterval: 30 * time.Millisecond,
+		SnapshotThreshold: 20,
+		Cache:             kv.Configuration{CleanInterval: 20 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := grpc.NewServer()
+	pb.RegisterStorageServer(grpcServer, NewReplicaServer(kv.NewNamespaces(node.Cache(), kv.Configuration{}), node))
+	pb.RegisterClusterServer(grpcServer, NewClusterServer(node))
+	go grpcServer.Serve(listener)
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testNode{
+		node:    node,
+		addr:    addr,
+		storage: pb.NewStorageClient(conn),
+		cluster: pb.NewClusterClient(conn),
+		stop: func() {
+			conn.Close()
+			grpcServer.Stop()
+			node.Close(context.Background())
+			transport.Close()
+		},
+	}
+}
+
+func TestCluster(t *testing.T) {
+	ctx := context.Background()
+	n1 := startNode(t, "n1", true)
+	defer n1.stop()
+	n2 := startNode(t, "n2", false)
+	defer n2.stop()
+	n3 := startNode(t, "n3", false)
+	defer n3.stop()
+	eventually(t, "bootstrap", n1.node.Writable)
+
+	if _, err := n1.cluster.AddMember(ctx, &pb.Member{Id: "n2", Address: n2.addr}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 30; i++ {
+		if _, err := n1.storage.Add(ctx, &pb.KeyValue{Key: string(rune('a' + i)), Value: &pb.T{Value: []byte{byte(i)}}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The log is compacted by now, so the new member receives the snapshot.
+	if _, err := n1.cluster.AddMember(ctx, &pb.Member{Id: "n3", Address: n3.addr}); err != nil {
+		t.Fatal(err)
+	}
+	n1.storage.Add(ctx, &pb.KeyValue{Key: "last", Value: &pb.T{Value: []byte("last")}})
+	expected, _ := n1.node.Cache().Entry("last")
+	for _, n := range []*testNode{n2, n3} {
+		eventually(t, n.node.ID()+" to catch up", func() bool {
+			box, ok := n.node.Cache().Entry("last")
+			return ok && box.Version == expected.Version && len(n.node.Cache().ListAll()) == 31
+		})
+	}
+	members, _ := n3.cluster.Members(ctx, &pb.Empty{})
+	if len(members.Members) != 3 || members.Leader != "n1" {
+		t.Errorf("unexpected members %v", members)
+	}
+
+	_, err := n2.storage.Add(ctx, &pb.KeyValue{Key: "k", Value: &pb.T{Value: []byte("v")}})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected follower to reject writes, got %v", err)
+	}
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.Metadata["leader"] != n1.addr {
+			t.Errorf("expected the leader address, got %v", info)
+		}
+	}
+
+	// Only the leader expires keys, the followers delete them when the expiration is committed.
+	sub := n2.node.Cache().Subscribe(kv.SubscribeOptions{Key: "temporary"})
+	defer sub.Close()
+	n1.node.Cache().AddWithTtl("temporary", kv.T{V: []byte("v")}, 50*time.Millisecond)
+	for _, kind := range []kv.EventKind{kv.EventSet, kv.EventExpire} {
+		select {
+		case ev := <-sub.C:
+			if ev.Kind != kind {
+				t.Fatalf("expected event %d, got %d", kind, ev.Kind)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the expiration")
+		}
+	}
+
+	n1.stop()
+	var leader *testNode
+	eventually(t, "new leader", func() bool {
+		for _, n := range []*testNode{n2, n3} {
+			if n.node.Writable() {
+				leader = n
+				return true
+			}
+		}
+		return false
+	})
+	if _, err := leader.storage.Add(ctx, &pb.KeyValue{Key: "after", Value: &pb.T{Value: []byte("v")}}); err != nil {
+		t.Fatal(err)
+	}
+	other := n2
+	if leader == n2 {
+		other = n3
+	}
+	eventually(t, "write of the new leader", func() bool {
+		_, ok := other.node.Cache().Value("after")
+		return ok
+	})
+	if _, err := leader.cluster.RemoveMember(ctx, &pb.Member{Id: "n1"}); err != nil {
+		t.Fatal(err)
+	}
+	members, _ = leader.cluster.Members(ctx, &pb.Empty{})
+	if len(members.Members) != 2 {
+		t.Errorf("expected n1 to be removed, got %v", members)
+	}
+}
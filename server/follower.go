@@ -0,0 +1,223 @@
+package server
+
+import (
+	"context"
+	"github.com/golang/protobuf/ptypes"
+	"kv-ttl/kv"
+	"kv-ttl/pb"
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultRetryInterval is the pause before a follower reconnects to the leader.
+const DefaultRetryInterval = time.Second
+
+var followerEvents = map[pb.EventType]kv.EventKind{
+	pb.EventType_SET:    kv.EventSet,
+	pb.EventType_DELETE: kv.EventDelete,
+	pb.EventType_EXPIRE: kv.EventExpire,
+	pb.EventType_TTL:    kv.EventTtl,
+	pb.EventType_EVICT:  kv.EventEvict,
+	pb.EventType_SLIDE:  kv.EventSlide,
+}
+
+// FollowerOptions configures a Follower.
+type FollowerOptions struct {
+	// Leader is the address of the leader reported to clients.
+	Leader string
+	// RetryInterval is the pause before reconnecting, DefaultRetryInterval is used if it is not set.
+	RetryInterval time.Duration
+}
+
+// Follower replicates the namespaces of the leader into the local ones, creating and dropping
+// them like the leader. It loads the snapshots of the leader and then applies the changes streamed by it.
+// When the connection breaks the follower reconnects and loads new snapshots.
+type Follower struct {
+	namespaces *kv.Namespaces
+	client     pb.StorageClient
+	opts       FollowerOptions
+
+	mu        sync.Mutex
+	connected bool
+	// last is the leader time of the last applied change or heartbeat, received is when it came.
+	last     time.Time
+	received time.Time
+}
+
+// NewFollower creates the follower replicating the data of the leader the client is connected to.
+// The caches of the namespaces must be created by kv.NewCache with kv.Configuration.ReadOnly set,
+// so that they change only with the leader and never evict, expire or slide keys on their own.
+func NewFollower(namespaces *kv.Namespaces, client pb.StorageClient, opts FollowerOptions) *Follower {
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = DefaultRetryInterval
+	}
+	return &Follower{namespaces: namespaces, client: client, opts: opts}
+}
+
+// Run replicates the data until the context is done and returns its error.
+func (f *Follower) Run(ctx context.Context) error {
+	for {
+		err := f.replicate(ctx)
+		f.mu.Lock()
+		f.connected = false
+		f.mu.Unlock()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Println("replication:", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(f.opts.RetryInterval):
+		}
+	}
+}
+
+// replicate loads the snapshots and applies the changes until the stream breaks.
+func (f *Follower) replicate(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := f.client.Replicate(ctx, &pb.Empty{})
+	if err != nil {
+		return err
+	}
+	snapshots := make(map[string]map[string]kv.TtlBox)
+	replicated := make(map[string]bool)
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		switch msg.Kind {
+		case pb.ReplicationMessage_NAMESPACE_CREATE:
+			if err := f.create(ctx, msg.Namespace, msg.NamespaceInfo); err != nil {
+				return err
+			}
+			snapshots[msg.Namespace] = make(map[string]kv.TtlBox)
+			replicated[msg.Namespace] = true
+		case pb.ReplicationMessage_ENTRY:
+			if snapshot, ok := snapshots[msg.Namespace]; ok {
+				key, box := fromPbEntry(msg.Entry)
+				snapshot[key] = box
+			}
+			continue
+		case pb.ReplicationMessage_SNAPSHOT_END:
+			replica, err := f.replica(msg.Namespace)
+			if err != nil {
+				return err
+			}
+			replica.Load(snapshots[msg.Namespace])
+			delete(snapshots, msg.Namespace)
+		case pb.ReplicationMessage_SYNCED:
+			// The namespaces dropped by the leader while the follower was disconnected.
+			for _, info := range f.namespaces.List() {
+				if !replicated[info.Name] {
+					if err := f.drop(ctx, info.Name); err != nil {
+						return err
+					}
+				}
+			}
+			f.mu.Lock()
+			f.connected = true
+			f.mu.Unlock()
+		case pb.ReplicationMessage_EVENT:
+			replica, err := f.replica(msg.Namespace)
+			if err != nil {
+				return err
+			}
+			key, box := fromPbEntry(msg.Entry)
+			replica.Apply(kv.Event{Kind: followerEvents[msg.Event], Key: key, TtlBox: box})
+		case pb.ReplicationMessage_NAMESPACE_DROP:
+			delete(replicated, msg.Namespace)
+			if err := f.drop(ctx, msg.Namespace); err != nil {
+				return err
+			}
+		}
+		if t, err := ptypes.Timestamp(msg.Time); err == nil {
+			f.mu.Lock()
+			f.last, f.received = t, time.Now()
+			f.mu.Unlock()
+		}
+	}
+}
+
+// create makes the local namespace match the one of the leader, recreating it if the options differ.
+// The default namespace always exists and keeps its own options.
+func (f *Follower) create(ctx context.Context, name string, info *pb.NamespaceInfo) error {
+	if name == kv.DefaultNamespace {
+		return nil
+	}
+	opts, err := fromPbNamespace(info)
+	if err != nil {
+		return err
+	}
+	for _, local := range f.namespaces.List() {
+		if local.Name != name {
+			continue
+		}
+		if local.NamespaceOptions == opts {
+			return nil
+		}
+		if err := f.namespaces.Drop(ctx, name); err != nil {
+			return err
+		}
+	}
+	return f.namespaces.Create(name, opts)
+}
+
+// drop deletes the local namespace unless it is the default one.
+func (f *Follower) drop(ctx context.Context, name string) error {
+	if name == kv.DefaultNamespace {
+		return nil
+	}
+	if err := f.namespaces.Drop(ctx, name); err != nil && err != kv.ErrNamespaceNotFound {
+		return err
+	}
+	return nil
+}
+
+// replica returns the local cache of the namespace.
+func (f *Follower) replica(name string) (kv.Replica, error) {
+	cache, err := f.namespaces.Cache(name)
+	if err != nil {
+		return nil, err
+	}
+	return cache.(kv.Replica), nil
+}
+
+// Leader returns the address of the leader.
+func (f *Follower) Leader() string {
+	return f.opts.Leader
+}
+
+// Writable returns false, the changes are made by the leader only.
+func (f *Follower) Writable() bool {
+	return false
+}
+
+// Connected reports whether the follower has loaded the snapshots and receives the changes.
+func (f *Follower) Connected() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.connected
+}
+
+// Lag returns the time since the last change applied by the follower was made on the leader.
+// The leader sends heartbeats to idle followers, so the lag of an up to date follower
+// is about the network delay, while the lag of a disconnected one keeps growing.
+func (f *Follower) Lag() time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.last.IsZero() {
+		return 0
+	}
+	lag := f.received.Sub(f.last)
+	if lag < 0 {
+		lag = 0
+	}
+	if !f.connected {
+		lag += time.Since(f.received)
+	}
+	return lag
+}
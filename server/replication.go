@@ -0,0 +1,310 @@
+package server
+
+import (
+	"context"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"kv-ttl/kv"
+	"kv-ttl/pb"
+	"sync"
+	"time"
+)
+
+// replicationBuffer is the number of events that can wait for a follower.
+// A follower lagging behind more is disconnected and loads a new snapshot.
+const replicationBuffer = 16 * 1024
+
+// heartbeatInterval is how often the leader reports to idle followers that it is alive.
+var heartbeatInterval = time.Second
+
+// Replica reports the state of a node keeping a copy of the leader's data.
+type Replica interface {
+	// Leader returns the address of the leader.
+	Leader() string
+	// Writable reports whether the node accepts writes, i.e. is the leader itself.
+	Writable() bool
+	// Connected reports whether the node receives the changes of the leader.
+	Connected() bool
+	// Lag returns the time since the last change applied by the node was made on the leader.
+	Lag() time.Duration
+}
+
+// NewReplicaServer serves the namespaces kept in sync with the leader by the replica.
+// Reads are served from the local data. Unless the replica is writable, writes are
+// rejected with the FailedPrecondition status, pb.ReasonReadOnly and the address of the leader,
+// so clients can send them there. Namespaces cannot be created or dropped.
+func NewReplicaServer(namespaces *kv.Namespaces, replica Replica) pb.StorageServer {
+	return &cacheServer{namespaces: namespaces, replica: replica}
+}
+
+// writableCacheFor is like cacheFor for the requests changing the data, that are rejected by followers.
+func (c *cacheServer) writableCacheFor(namespace string) (kv.Cache, error) {
+	if err := c.checkWritable(); err != nil {
+		return nil, err
+	}
+	return c.cacheFor(namespace)
+}
+
+// checkWritable returns the status error if the server is a replica not accepting writes.
+func (c *cacheServer) checkWritable() error {
+	if c.replica == nil || c.replica.Writable() {
+		return nil
+	}
+	return readOnlyError(c.replica.Leader())
+}
+
+// readOnlyError is returned by the replicas other than the leader.
+func readOnlyError(leader string) error {
+	st := status.Newf(codes.FailedPrecondition, "replica is read-only, write to the leader %s", leader)
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   pb.ReasonReadOnly,
+		Domain:   pb.ErrorDomain,
+		Metadata: map[string]string{"leader": leader},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// Replicate streams the snapshots of all the namespaces followed by their changes until
+// the follower cancels the call. Every namespace is subscribed to before its snapshot is taken,
+// so the changes made meanwhile are streamed after it. Namespaces created or dropped later
+// are streamed as well. A follower that doesn't keep up is disconnected with the ResourceExhausted status.
+func (c *cacheServer) Replicate(req *pb.Empty, stream pb.Storage_ReplicateServer) error {
+	r := &replicationStream{
+		stream:     stream,
+		out:        make(chan *pb.ReplicationMessage, replicationBuffer),
+		errs:       make(chan error, 1),
+		namespaces: make(map[string]*namespaceStream),
+	}
+	defer r.stopAll()
+	var mu sync.Mutex
+	var pending []kv.NamespaceEvent
+	changed := make(chan struct{}, 1)
+	unwatch := c.namespaces.Watch(func(ev kv.NamespaceEvent) {
+		mu.Lock()
+		pending = append(pending, ev)
+		mu.Unlock()
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+	defer unwatch()
+	apply := func() error {
+		mu.Lock()
+		events := pending
+		pending = nil
+		mu.Unlock()
+		for _, ev := range events {
+			if err := r.apply(ev); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := apply(); err != nil {
+		return err
+	}
+	if err := stream.Send(replicationMessage(pb.ReplicationMessage_SYNCED, time.Now())); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case err := <-r.errs:
+			return err
+		case <-changed:
+			if err := apply(); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			if err := stream.Send(replicationMessage(pb.ReplicationMessage_HEARTBEAT, time.Now())); err != nil {
+				return err
+			}
+		case msg := <-r.out:
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// replicationStream sends the messages of a Replicate call. The changes of every namespace
+// are forwarded to out by its own goroutine, only the goroutine of the call sends them.
+type replicationStream struct {
+	stream     pb.Storage_ReplicateServer
+	out        chan *pb.ReplicationMessage
+	errs       chan error
+	namespaces map[string]*namespaceStream
+}
+
+type namespaceStream struct {
+	quit chan struct{}
+	done chan struct{}
+}
+
+// apply starts streaming the created namespace or reports the dropped one.
+func (r *replicationStream) apply(ev kv.NamespaceEvent) error {
+	if err := r.stop(ev.Name); err != nil {
+		return err
+	}
+	if ev.Dropped {
+		msg := replicationMessage(pb.ReplicationMessage_NAMESPACE_DROP, time.Now())
+		msg.Namespace = ev.Name
+		return r.stream.Send(msg)
+	}
+	return r.start(ev)
+}
+
+// start sends the snapshot of the namespace and starts forwarding its changes.
+func (r *replicationStream) start(ev kv.NamespaceEvent) error {
+	sub := ev.Cache.Subscribe(kv.SubscribeOptions{Buffer: replicationBuffer, Slides: true})
+	msg := replicationMessage(pb.ReplicationMessage_NAMESPACE_CREATE, time.Now())
+	msg.Namespace = ev.Name
+	msg.NamespaceInfo = toPbNamespace(ev.NamespaceInfo)
+	if err := r.stream.Send(msg); err != nil {
+		sub.Close()
+		return err
+	}
+	for key, box := range ev.Cache.(kv.Replica).Copy() {
+		msg := &pb.ReplicationMessage{Kind: pb.ReplicationMessage_ENTRY, Namespace: ev.Name, Entry: toPbEntry(key, box)}
+		if err := r.stream.Send(msg); err != nil {
+			sub.Close()
+			return err
+		}
+	}
+	msg = replicationMessage(pb.ReplicationMessage_SNAPSHOT_END, time.Now())
+	msg.Namespace = ev.Name
+	if err := r.stream.Send(msg); err != nil {
+		sub.Close()
+		return err
+	}
+	ns := &namespaceStream{quit: make(chan struct{}), done: make(chan struct{})}
+	r.namespaces[ev.Name] = ns
+	go r.forward(ev.Name, sub, ns)
+	return nil
+}
+
+// forward passes the changes of the namespace to out until the namespace stream is stopped
+// or the cache is closed, which happens when the namespace is dropped.
+func (r *replicationStream) forward(name string, sub *kv.Subscription, ns *namespaceStream) {
+	defer close(ns.done)
+	defer sub.Close()
+	for {
+		select {
+		case <-ns.quit:
+			return
+		case ev, ok := <-sub.C:
+			if !ok {
+				if sub.Err() != nil {
+					select {
+					case r.errs <- status.Error(codes.ResourceExhausted, sub.Err().Error()):
+					default:
+					}
+				}
+				return
+			}
+			msg := replicationMessage(pb.ReplicationMessage_EVENT, ev.Time)
+			msg.Namespace = name
+			msg.Event = eventTypes[ev.Kind]
+			msg.Entry = toPbEntry(ev.Key, ev.TtlBox)
+			select {
+			case r.out <- msg:
+			case <-ns.quit:
+				return
+			}
+		}
+	}
+}
+
+// stop ends forwarding the changes of the namespace and sends the ones already forwarded,
+// so that nothing of the namespace follows its drop.
+func (r *replicationStream) stop(name string) error {
+	ns, ok := r.namespaces[name]
+	if !ok {
+		return nil
+	}
+	delete(r.namespaces, name)
+	close(ns.quit)
+	<-ns.done
+	for {
+		select {
+		case msg := <-r.out:
+			if err := r.stream.Send(msg); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+func (r *replicationStream) stopAll() {
+	for _, ns := range r.namespaces {
+		close(ns.quit)
+	}
+}
+
+// ReplicationStatus reports the role of the server and the replication lag of followers.
+func (c *cacheServer) ReplicationStatus(ctx context.Context, req *pb.Empty) (*pb.ReplicationInfo, error) {
+	if c.replica == nil {
+		return &pb.ReplicationInfo{Role: pb.ReplicationInfo_LEADER, Connected: true, Lag: ptypes.DurationProto(0)}, nil
+	}
+	role := pb.ReplicationInfo_FOLLOWER
+	if c.replica.Writable() {
+		role = pb.ReplicationInfo_LEADER
+	}
+	return &pb.ReplicationInfo{
+		Role:      role,
+		Leader:    c.replica.Leader(),
+		Connected: c.replica.Connected(),
+		Lag:       ptypes.DurationProto(c.replica.Lag()),
+	}, nil
+}
+
+func replicationMessage(kind pb.ReplicationMessage_Kind, t time.Time) *pb.ReplicationMessage {
+	stamp, _ := ptypes.TimestampProto(t)
+	return &pb.ReplicationMessage{Kind: kind, Time: stamp}
+}
+
+// toPbEntry converts the cache entry into the full state of the key sent to followers.
+func toPbEntry(key string, box kv.TtlBox) *pb.Entry {
+	value := toPb(box.Content)
+	value.Version = box.Version
+	created, _ := ptypes.TimestampProto(box.CreatedAt)
+	entry := &pb.Entry{Key: key, Value: value, CreatedAt: created}
+	if box.Expired != nil {
+		entry.Expired, _ = ptypes.TimestampProto(*box.Expired)
+	}
+	if box.Sliding > 0 {
+		entry.Sliding = ptypes.DurationProto(box.Sliding)
+	}
+	return entry
+}
+
+// fromPbEntry converts the entry streamed by the leader into the cache entry.
+func fromPbEntry(entry *pb.Entry) (string, kv.TtlBox) {
+	box := kv.TtlBox{
+		Content: fromPb(entry.Value),
+		Version: entry.GetValue().GetVersion(),
+	}
+	box.CreatedAt, _ = ptypes.Timestamp(entry.CreatedAt)
+	if entry.Expired != nil {
+		expired, err := ptypes.Timestamp(entry.Expired)
+		if err == nil {
+			box.Expired = &expired
+		}
+	}
+	if entry.Sliding != nil {
+		box.Sliding, _ = ptypes.Duration(entry.Sliding)
+	}
+	return entry.Key, box
+}
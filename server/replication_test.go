@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"kv-ttl/kv"
+	"kv-ttl/pb"
+	"net"
+	"testing"
+	"time"
+)
+
+// serve starts the gRPC server on localhost and returns its address and the connected client.
+func serve(t *testing.T, srv pb.StorageServer) (string, pb.StorageClient, func()) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grpcServer := grpc.NewServer()
+	pb.RegisterStorageServer(grpcServer, srv)
+	go grpcServer.Serve(listener)
+	addr := listener.Addr().String()
+	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr, pb.NewStorageClient(conn), func() {
+		conn.Close()
+		grpcServer.Stop()
+	}
+}
+
+// eventually fails the test if the condition doesn't become true within a few seconds.
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReplication(t *testing.T) {
+	leader := kv.NewCache(kv.Configuration{CleanInterval: 10 * time.Millisecond})
+	defer leader.Close(context.Background())
+	leader.Add("a", kv.T{V: []byte("a")})
+	leader.AddWithTtl("b", kv.T{V: []byte("b")}, time.Hour)
+	leaderAddr, leaderClient, stopLeader := serve(t, NewCacheServer(leader))
+	defer stopLeader()
+
+	replica := kv.NewCache(kv.Configuration{CleanInterval: time.Hour, ReadOnly: true})
+	defer replica.Close(context.Background())
+	namespaces := kv.NewNamespaces(replica, kv.Configuration{ReadOnly: true})
+	follower := NewFollower(namespaces, leaderClient, FollowerOptions{Leader: leaderAddr, RetryInterval: 10 * time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		follower.Run(ctx)
+	}()
+	_, client, stopFollower := serve(t, NewReplicaServer(namespaces, follower))
+	defer stopFollower()
+
+	eventually(t, "snapshot", follower.Connected)
+	expected, _ := leader.Entry("b")
+	if box, ok := replica.Entry("b"); !ok || box.Version != expected.Version || !box.Expired.Equal(*expected.Expired) {
+		t.Errorf("expected %v to be replicated, got %v", expected, box)
+	}
+	v, err := client.Value(context.Background(), &pb.Key{Key: "a"})
+	if err != nil || string(v.Value) != "a" {
+		t.Errorf("expected follower to serve reads, got %v %v", v, err)
+	}
+
+	sub := replica.Subscribe(kv.SubscribeOptions{})
+	defer sub.Close()
+	leader.Add("c", kv.T{V: []byte("c")})
+	leader.Remove("a")
+	leader.Persist("b")
+	leader.AddWithTtl("d", kv.T{V: []byte("d")}, 20*time.Millisecond)
+	var kinds []kv.EventKind
+	for len(kinds) < 5 {
+		select {
+		case ev := <-sub.C:
+			kinds = append(kinds, ev.Kind)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for changes, got %v", kinds)
+		}
+	}
+	expectedKinds := []kv.EventKind{kv.EventSet, kv.EventDelete, kv.EventTtl, kv.EventSet, kv.EventExpire}
+	for i := range kinds {
+		if kinds[i] != expectedKinds[i] {
+			t.Fatalf("expected events %v, got %v", expectedKinds, kinds)
+		}
+	}
+	if box, ok := replica.Entry("b"); !ok || box.Expired != nil {
+		t.Errorf("expected b to be persisted, got %v", box)
+	}
+
+	_, err = client.Add(context.Background(), &pb.KeyValue{Key: "e", Value: &pb.T{Value: []byte("e")}})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected follower to reject writes, got %v", err)
+	}
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); !ok || info.Reason != pb.ReasonReadOnly || info.Metadata["leader"] != leaderAddr {
+			t.Errorf("expected read-only details with the leader, got %v", d)
+		}
+	}
+	info, err := client.ReplicationStatus(context.Background(), &pb.Empty{})
+	if err != nil || info.Role != pb.ReplicationInfo_FOLLOWER || !info.Connected || info.Leader != leaderAddr {
+		t.Errorf("unexpected follower status %v %v", info, err)
+	}
+	if lag, _ := ptypes.Duration(info.GetLag()); lag > time.Second {
+		t.Errorf("expected small lag, got %v", lag)
+	}
+
+	// A follower reconnecting loads the new snapshot dropping the keys deleted meanwhile.
+	cancel()
+	<-done
+	leader.Remove("c")
+	leader.Add("f", kv.T{V: []byte("f")})
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	go follower.Run(ctx)
+	eventually(t, "new snapshot", func() bool {
+		_, c := replica.Value("c")
+		_, f := replica.Value("f")
+		return !c && f
+	})
+}
+
+func TestReplicationNamespaces(t *testing.T) {
+	ctx := context.Background()
+	leader := kv.NewNamespaces(kv.NewCache(kv.Configuration{}), kv.Configuration{})
+	defer leader.Close(ctx)
+	if err := leader.Create("before", kv.NamespaceOptions{MaxEntries: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := leader.Create("options", kv.NamespaceOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := leader.Cache("before")
+	before.Add("a", kv.T{V: []byte("a")})
+	before.AddWithSlidingTtl("b", kv.T{V: []byte("b")}, time.Hour)
+	leaderAddr, leaderClient, stopLeader := serve(t, NewNamespacedServer(leader))
+	defer stopLeader()
+
+	// The namespaces the leader doesn't have are dropped, the ones with other options are recreated.
+	namespaces := kv.NewNamespaces(kv.NewCache(kv.Configuration{ReadOnly: true}), kv.Configuration{ReadOnly: true})
+	defer namespaces.Close(ctx)
+	namespaces.Create("stale", kv.NamespaceOptions{})
+	namespaces.Create("options", kv.NamespaceOptions{MaxEntries: 5})
+	follower := NewFollower(namespaces, leaderClient, FollowerOptions{Leader: leaderAddr, RetryInterval: 10 * time.Millisecond})
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go follower.Run(runCtx)
+	eventually(t, "snapshots", follower.Connected)
+	if infos := namespaces.List(); fmt.Sprint(infos) != fmt.Sprint(leader.List()) {
+		t.Errorf("expected the namespaces of the leader %v, got %v", leader.List(), infos)
+	}
+	replica, err := namespaces.Cache("before")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(replica.ListAll()); n != 2 {
+		t.Errorf("expected the snapshot of the namespace, got %d values", n)
+	}
+
+	// Reads on the follower don't renew sliding TTLs, only the leader's renewals do.
+	expected := before.(kv.Replica).Copy()["b"]
+	replica.Value("b")
+	if box := replica.(kv.Replica).Copy()["b"]; !box.Expired.Equal(*expected.Expired) {
+		t.Errorf("expected the follower not to slide the TTL, got %v instead of %v", box.Expired, expected.Expired)
+	}
+
+	if err := leader.Create("after", kv.NamespaceOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := leader.Cache("after")
+	after.Add("c", kv.T{V: []byte("c")})
+	eventually(t, "new namespace", func() bool {
+		cache, err := namespaces.Cache("after")
+		if err != nil {
+			return false
+		}
+		_, ok := cache.Value("c")
+		return ok
+	})
+
+	// Only the leader evicts, the follower deletes the keys evicted by the leader.
+	before.Add("d", kv.T{V: []byte("d")})
+	before.Add("e", kv.T{V: []byte("e")})
+	eventually(t, "eviction", func() bool {
+		// The empty key holds the greatest version of the cache.
+		entries := replica.(kv.Replica).Copy()
+		if len(entries) != 3 {
+			return false
+		}
+		for k, box := range before.(kv.Replica).Copy() {
+			if k != "" && entries[k].Version != box.Version {
+				return false
+			}
+		}
+		return true
+	})
+	if s := replica.Stats(); s.Evictions != 0 {
+		t.Errorf("expected the follower not to evict, got %+v", s)
+	}
+
+	if err := leader.Drop(ctx, "after"); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "drop", func() bool {
+		_, err := namespaces.Cache("after")
+		return err == kv.ErrNamespaceNotFound
+	})
+}
@@ -2,57 +2,223 @@ package server
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/duration"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"kv-ttl/kv"
 	"kv-ttl/pb"
+	"time"
 )
 
-const (
-	notFound  = "not_found"
-	duplicate = "duplicate"
-)
+// maxTtl limits TTLs accepted by the server, longer ones are most likely mistakes.
+const maxTtl = 10 * 365 * 24 * time.Hour
+
+// maxScanCount limits the number of items returned by a single Scan call.
+const maxScanCount = 1000
+
+var kvErrors = []struct {
+	err    error
+	code   codes.Code
+	reason string
+}{
+	{kv.ErrNotFound, codes.NotFound, pb.ReasonNotFound},
+	{kv.ErrExists, codes.AlreadyExists, pb.ReasonExists},
+	{kv.ErrVersionMismatch, codes.FailedPrecondition, pb.ReasonVersionMismatch},
+	{kv.ErrNotNumeric, codes.FailedPrecondition, pb.ReasonNotNumeric},
+	{kv.ErrOverflow, codes.OutOfRange, pb.ReasonOverflow},
+	{kv.ErrInvalidTtl, codes.InvalidArgument, pb.ReasonInvalidTtl},
+	{kv.ErrInvalidKey, codes.InvalidArgument, pb.ReasonInvalidKey},
+	{kv.ErrTooLarge, codes.InvalidArgument, pb.ReasonTooLarge},
+	{kv.ErrConflict, codes.Aborted, pb.ReasonConflict},
+	{kv.ErrLocked, codes.FailedPrecondition, pb.ReasonLocked},
+	{kv.ErrNotOwner, codes.FailedPrecondition, pb.ReasonNotOwner},
+	{kv.ErrNamespaceNotFound, codes.NotFound, pb.ReasonNamespaceNotFound},
+	{kv.ErrNamespaceExists, codes.AlreadyExists, pb.ReasonNamespaceExists},
+	{kv.ErrInvalidNamespace, codes.InvalidArgument, pb.ReasonInvalidNamespace},
+	{kv.ErrReadOnly, codes.FailedPrecondition, pb.ReasonReadOnly},
+	{kv.ErrUnavailable, codes.Unavailable, pb.ReasonUnavailable},
+}
 
-// cacheServer implements StorageServer interface. Maps cache methods to server methods.
+// errNamespacesReplicated is returned by replicas, their namespaces are not changed directly.
+var errNamespacesReplicated = status.Error(codes.FailedPrecondition, "namespaces cannot be changed on replicas")
+
+var eventTypes = map[kv.EventKind]pb.EventType{
+	kv.EventSet:    pb.EventType_SET,
+	kv.EventDelete: pb.EventType_DELETE,
+	kv.EventExpire: pb.EventType_EXPIRE,
+	kv.EventTtl:    pb.EventType_TTL,
+	kv.EventEvict:  pb.EventType_EVICT,
+	kv.EventSlide:  pb.EventType_SLIDE,
+}
+
+var writeModes = map[pb.WriteMode]kv.WriteMode{
+	pb.WriteMode_ALWAYS:     kv.WriteAlways,
+	pb.WriteMode_IF_ABSENT:  kv.WriteIfAbsent,
+	pb.WriteMode_IF_PRESENT: kv.WriteIfPresent,
+}
+
+// cacheServer implements StorageServer interface. Maps cache methods to server methods
+// calling the cache of the namespace given in the request.
 type cacheServer struct {
-	cache kv.Cache
+	namespaces *kv.Namespaces
+	// replica is set if the server is a replication follower.
+	replica Replica
 }
 
+// NewCacheServer serves the cache as the default namespace, other namespaces are kept in memory.
 func NewCacheServer(cache kv.Cache) pb.StorageServer {
-	return &cacheServer{cache: cache}
+	return NewNamespacedServer(kv.NewNamespaces(cache, kv.Configuration{}))
+}
+
+func NewNamespacedServer(namespaces *kv.Namespaces) pb.StorageServer {
+	return &cacheServer{namespaces: namespaces}
+}
+
+// cacheFor returns the cache of the namespace or the status error if there is no such namespace.
+func (c *cacheServer) cacheFor(namespace string) (kv.Cache, error) {
+	cache, err := c.namespaces.Cache(namespace)
+	if err != nil {
+		return nil, namespaceError(err, namespace)
+	}
+	return cache, nil
+}
+
+func (c *cacheServer) CreateNamespace(ctx context.Context, req *pb.NamespaceInfo) (*pb.Empty, error) {
+	if c.replica != nil {
+		return nil, errNamespacesReplicated
+	}
+	opts, err := fromPbNamespace(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.namespaces.Create(req.Name, opts); err != nil {
+		return nil, namespaceError(err, req.Name)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (c *cacheServer) ListNamespaces(ctx context.Context, req *pb.Empty) (*pb.NamespaceList, error) {
+	infos := c.namespaces.List()
+	resp := &pb.NamespaceList{Namespaces: make([]*pb.NamespaceInfo, len(infos))}
+	for i, info := range infos {
+		resp.Namespaces[i] = toPbNamespace(info)
+	}
+	return resp, nil
+}
+
+func toPbNamespace(info kv.NamespaceInfo) *pb.NamespaceInfo {
+	ns := &pb.NamespaceInfo{
+		Name:       info.Name,
+		MaxEntries: int64(info.MaxEntries),
+		MaxBytes:   info.MaxBytes,
+	}
+	if info.DefaultTtl > 0 {
+		ns.DefaultTtl = ptypes.DurationProto(info.DefaultTtl)
+	}
+	return ns
+}
+
+// fromPbNamespace validates the options of the namespace.
+func fromPbNamespace(req *pb.NamespaceInfo) (kv.NamespaceOptions, error) {
+	if req.MaxEntries < 0 || req.MaxBytes < 0 {
+		return kv.NamespaceOptions{}, status.Error(codes.InvalidArgument, "size limits must not be negative")
+	}
+	opts := kv.NamespaceOptions{MaxEntries: int(req.MaxEntries), MaxBytes: req.MaxBytes}
+	if req.DefaultTtl != nil {
+		dur, err := requiredTtl(req.DefaultTtl)
+		if err != nil {
+			return kv.NamespaceOptions{}, namespaceError(err, req.Name)
+		}
+		opts.DefaultTtl = dur
+	}
+	return opts, nil
+}
+
+func (c *cacheServer) DropNamespace(ctx context.Context, req *pb.Namespace) (*pb.Empty, error) {
+	if c.replica != nil {
+		return nil, errNamespacesReplicated
+	}
+	if err := c.namespaces.Drop(ctx, req.Namespace); err != nil {
+		return nil, namespaceError(err, req.Namespace)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (c *cacheServer) Stats(ctx context.Context, req *pb.Namespace) (*pb.StatsResponse, error) {
+	cache, err := c.cacheFor(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	stats := cache.Stats()
+	return &pb.StatsResponse{Evictions: stats.Evictions, Expirations: stats.Expirations}, nil
 }
 
 func (c *cacheServer) Add(ctx context.Context, r *pb.KeyValue) (*pb.Empty, error) {
-	ok := c.cache.Add(r.Key, kv.T{V: r.Value.Value})
-	if ok {
-		return &pb.Empty{}, fmt.Errorf(duplicate)
+	cache, err := c.writableCacheFor(r.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if err := cache.Add(r.Key, fromPb(r.Value)); err != nil {
+		return nil, statusError(err, r.Key)
 	}
 	return &pb.Empty{}, nil
 }
 
 func (c *cacheServer) AddWithTtl(ctx context.Context, req *pb.KeyValueTtl) (*pb.Empty, error) {
-	dur, err := ptypes.Duration(req.Ttl)
+	cache, err := c.writableCacheFor(req.Namespace)
 	if err != nil {
 		return nil, err
 	}
-	ok := c.cache.AddWithTtl(req.Key, kv.T{V: req.Value.Value}, dur)
-	if !ok {
-		return &pb.Empty{}, fmt.Errorf(duplicate)
+	dur, err := requiredTtl(req.Ttl)
+	if err != nil {
+		return nil, statusError(err, req.Key)
+	}
+	add := cache.AddWithTtl
+	if req.Sliding {
+		add = cache.AddWithSlidingTtl
+	}
+	if err := add(req.Key, fromPb(req.Value), dur); err != nil {
+		return nil, statusError(err, req.Key)
 	}
 	return &pb.Empty{}, nil
 }
 
 func (c *cacheServer) Value(ctx context.Context, r *pb.Key) (*pb.T, error) {
-	value, ok := c.cache.Value(r.Key)
+	cache, err := c.cacheFor(r.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	box, ok := cache.Entry(r.Key)
 	if !ok {
-		return &pb.T{Value: value.V}, fmt.Errorf(notFound)
+		return nil, statusError(kv.ErrNotFound, r.Key)
 	}
-	return &pb.T{Value: value.V}, nil
+	t := toPb(box.Content)
+	t.Version = box.Version
+	return t, nil
 }
 
-func (c *cacheServer) ListAll(req *pb.Empty, stream pb.Storage_ListAllServer) error {
-	for _, v := range c.cache.ListAll() {
-		if err := stream.Send(&pb.T{Value: v.V}); err != nil {
+func (c *cacheServer) Touch(ctx context.Context, r *pb.Key) (*pb.Empty, error) {
+	cache, err := c.writableCacheFor(r.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if err := cache.Touch(r.Key); err != nil {
+		return nil, statusError(err, r.Key)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (c *cacheServer) ListAll(req *pb.Namespace, stream pb.Storage_ListAllServer) error {
+	cache, err := c.cacheFor(req.Namespace)
+	if err != nil {
+		return err
+	}
+	for _, v := range cache.ListAll() {
+		if err := stream.Send(toPb(v)); err != nil {
 			return err
 		}
 	}
@@ -60,26 +226,428 @@ func (c *cacheServer) ListAll(req *pb.Empty, stream pb.Storage_ListAllServer) er
 }
 
 func (c *cacheServer) Remove(ctx context.Context, req *pb.Key) (*pb.Empty, error) {
-	c.cache.Remove(req.Key)
-	return nil, nil
+	cache, err := c.writableCacheFor(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if err := cache.Remove(req.Key); err != nil {
+		return nil, statusError(err, req.Key)
+	}
+	return &pb.Empty{}, nil
 }
 
 func (c *cacheServer) TimeAlive(ctx context.Context, req *pb.Key) (*pb.TtlResponse, error) {
-	dur, ok := c.cache.TimeAlive(req.Key)
+	cache, err := c.cacheFor(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	dur, ok := cache.TimeAlive(req.Key)
 	if !ok {
-		return &pb.TtlResponse{}, fmt.Errorf(notFound)
+		return nil, statusError(kv.ErrNotFound, req.Key)
 	}
 	return &pb.TtlResponse{Ttl: ptypes.DurationProto(dur)}, nil
 }
 
+func (c *cacheServer) Ttl(ctx context.Context, req *pb.Key) (*pb.TtlInfo, error) {
+	cache, err := c.cacheFor(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	remaining, expired, ok := cache.Ttl(req.Key)
+	if !ok {
+		return nil, statusError(kv.ErrNotFound, req.Key)
+	}
+	if expired == nil {
+		return &pb.TtlInfo{NoExpiry: true}, nil
+	}
+	stamp, _ := ptypes.TimestampProto(*expired)
+	return &pb.TtlInfo{Remaining: ptypes.DurationProto(remaining), Expired: stamp}, nil
+}
+
 func (c *cacheServer) SetTtl(ctx context.Context, req *pb.TtlRequest) (*pb.Empty, error) {
+	cache, err := c.writableCacheFor(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
 	t, err := ptypes.Timestamp(req.Stamp)
 	if err != nil {
-		return &pb.Empty{}, err
+		return nil, statusError(kv.ErrInvalidTtl, req.Key)
+	}
+	if err := cache.SetTtl(req.Key, &t); err != nil {
+		return nil, statusError(err, req.Key)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (c *cacheServer) Expire(ctx context.Context, req *pb.ExpireRequest) (*pb.Empty, error) {
+	cache, err := c.writableCacheFor(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	dur, err := requiredTtl(req.Ttl)
+	if err != nil {
+		return nil, statusError(err, req.Key)
+	}
+	if err := cache.Expire(req.Key, dur); err != nil {
+		return nil, statusError(err, req.Key)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (c *cacheServer) Persist(ctx context.Context, req *pb.Key) (*pb.Empty, error) {
+	cache, err := c.writableCacheFor(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if err := cache.Persist(req.Key); err != nil {
+		return nil, statusError(err, req.Key)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (c *cacheServer) Set(ctx context.Context, req *pb.SetRequest) (*pb.SetResponse, error) {
+	cache, err := c.writableCacheFor(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	mode, ok := writeModes[req.Mode]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown write mode: %v", req.Mode)
+	}
+	dur, err := optionalTtl(req.Ttl)
+	if err != nil {
+		return nil, statusError(err, req.Key)
+	}
+	written, err := cache.Set(req.Key, fromPb(req.Value), dur, mode)
+	if err != nil {
+		return nil, statusError(err, req.Key)
+	}
+	return &pb.SetResponse{Written: written}, nil
+}
+
+func (c *cacheServer) CompareAndSwap(ctx context.Context, req *pb.CasRequest) (*pb.CasResponse, error) {
+	cache, err := c.writableCacheFor(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	dur, err := optionalTtl(req.Ttl)
+	if err != nil {
+		return nil, statusError(err, req.Key)
+	}
+	version, err := cache.CompareAndSwap(req.Key, req.Version, fromPb(req.Value), dur)
+	if err != nil {
+		return nil, statusError(err, req.Key)
+	}
+	return &pb.CasResponse{Version: version}, nil
+}
+
+func (c *cacheServer) Scan(ctx context.Context, req *pb.ScanRequest) (*pb.ScanResponse, error) {
+	cache, err := c.cacheFor(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if req.Count < 0 || req.Count > maxScanCount {
+		return nil, status.Errorf(codes.InvalidArgument, "count must be between 0 and %d", maxScanCount)
+	}
+	items, next := cache.Scan(kv.ScanOptions{
+		Cursor: req.Cursor,
+		Count:  int(req.Count),
+		Prefix: req.Prefix,
+		Match:  req.Match,
+	})
+	resp := &pb.ScanResponse{Items: make([]*pb.Item, len(items)), NextCursor: next}
+	for i, item := range items {
+		resp.Items[i] = toPbItem(item)
+	}
+	return resp, nil
+}
+
+func (c *cacheServer) MultiGet(ctx context.Context, req *pb.Keys) (*pb.MultiGetResponse, error) {
+	cache, err := c.cacheFor(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	results := cache.MultiGet(req.Keys)
+	resp := &pb.MultiGetResponse{Results: make([]*pb.GetResult, len(results))}
+	for i, r := range results {
+		resp.Results[i] = &pb.GetResult{Key: req.Keys[i], Found: r.Found}
+		if r.Found {
+			resp.Results[i].Value = toPb(r.Content)
+			resp.Results[i].Value.Version = r.Version
+		}
 	}
-	ok := c.cache.SetTtl(req.Key, &t)
-	if ok {
-		return &pb.Empty{}, fmt.Errorf(notFound)
+	return resp, nil
+}
+
+func (c *cacheServer) MultiSet(ctx context.Context, req *pb.MultiSetRequest) (*pb.MultiSetResponse, error) {
+	cache, err := c.writableCacheFor(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]kv.SetItem, len(req.Items))
+	for i, item := range req.Items {
+		mode, ok := writeModes[item.Mode]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown write mode: %v", item.Mode)
+		}
+		dur, err := optionalTtl(item.Ttl)
+		if err != nil {
+			return nil, statusError(err, item.Key)
+		}
+		items[i] = kv.SetItem{Key: item.Key, Value: fromPb(item.Value), Ttl: dur, Mode: mode}
+	}
+	written, err := cache.MultiSet(items)
+	var keyErr *kv.KeyError
+	if errors.As(err, &keyErr) {
+		return nil, statusError(keyErr.Err, keyErr.Key)
+	}
+	if err != nil {
+		return nil, namespaceError(err, req.Namespace)
+	}
+	resp := &pb.MultiSetResponse{Results: make([]*pb.SetResult, len(written))}
+	for i, w := range written {
+		resp.Results[i] = &pb.SetResult{Key: items[i].Key, Written: w}
+	}
+	return resp, nil
+}
+
+func (c *cacheServer) MultiDelete(ctx context.Context, req *pb.Keys) (*pb.MultiDeleteResponse, error) {
+	cache, err := c.writableCacheFor(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := cache.MultiDelete(req.Keys)
+	if err != nil {
+		return nil, namespaceError(err, req.Namespace)
+	}
+	resp := &pb.MultiDeleteResponse{Results: make([]*pb.DeleteResult, len(deleted))}
+	for i, d := range deleted {
+		resp.Results[i] = &pb.DeleteResult{Key: req.Keys[i], Deleted: d}
+	}
+	return resp, nil
+}
+
+func (c *cacheServer) Incr(ctx context.Context, req *pb.IncrRequest) (*pb.IncrResponse, error) {
+	cache, err := c.writableCacheFor(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	dur, err := optionalTtl(req.Ttl)
+	if err != nil {
+		return nil, statusError(err, req.Key)
+	}
+	value, err := cache.Incr(req.Key, req.Delta, dur, req.ResetTtl)
+	if err != nil {
+		return nil, statusError(err, req.Key)
+	}
+	return &pb.IncrResponse{Value: value}, nil
+}
+
+// Txn checks the conditions and applies the ops in a single transaction.
+// The transaction is not applied and the index of the failed check is returned
+// if a condition doesn't hold.
+func (c *cacheServer) Txn(ctx context.Context, req *pb.TxnRequest) (*pb.TxnResponse, error) {
+	cache, err := c.writableCacheFor(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	ttls := make([]time.Duration, len(req.Ops))
+	for i, op := range req.Ops {
+		if op.Kind != pb.TxnOp_SET && op.Kind != pb.TxnOp_DELETE {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown op kind: %v", op.Kind)
+		}
+		if ttls[i], err = optionalTtl(op.Ttl); err != nil {
+			return nil, statusError(err, op.Key)
+		}
+	}
+	var resp *pb.TxnResponse
+	err = cache.Update(func(tx kv.Txn) error {
+		for i, check := range req.Checks {
+			box, ok := tx.Get(check.Key)
+			if !checkPasses(check, box, ok) {
+				resp = &pb.TxnResponse{FailedCheck: int32(i)}
+				return nil
+			}
+		}
+		for i, op := range req.Ops {
+			if op.Kind == pb.TxnOp_DELETE {
+				tx.Delete(op.Key)
+			} else {
+				tx.Set(op.Key, fromPb(op.Value), ttls[i])
+			}
+		}
+		resp = &pb.TxnResponse{Committed: true}
+		return nil
+	})
+	if err != nil {
+		return nil, statusError(err, "")
+	}
+	return resp, nil
+}
+
+// checkPasses reports whether the entry read in a transaction satisfies the check.
+func checkPasses(check *pb.TxnCheck, box kv.TtlBox, ok bool) bool {
+	switch check.Kind {
+	case pb.TxnCheck_EXISTS:
+		return ok
+	case pb.TxnCheck_ABSENT:
+		return !ok
+	default:
+		return box.Version == check.Version
+	}
+}
+
+func (c *cacheServer) Lock(ctx context.Context, req *pb.LockRequest) (*pb.LockResponse, error) {
+	cache, err := c.writableCacheFor(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	ttl, err := requiredTtl(req.Ttl)
+	if err != nil {
+		return nil, statusError(err, req.Name)
+	}
+	token, err := cache.Lock(req.Name, req.Owner, ttl)
+	if err != nil {
+		return nil, statusError(err, req.Name)
+	}
+	return &pb.LockResponse{Token: token}, nil
+}
+
+func (c *cacheServer) Renew(ctx context.Context, req *pb.LockRequest) (*pb.Empty, error) {
+	cache, err := c.writableCacheFor(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	ttl, err := requiredTtl(req.Ttl)
+	if err != nil {
+		return nil, statusError(err, req.Name)
+	}
+	if err := cache.Renew(req.Name, req.Owner, req.Token, ttl); err != nil {
+		return nil, statusError(err, req.Name)
+	}
+	return &pb.Empty{}, nil
+}
+
+func (c *cacheServer) Unlock(ctx context.Context, req *pb.LockRequest) (*pb.Empty, error) {
+	cache, err := c.writableCacheFor(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if err := cache.Unlock(req.Name, req.Owner, req.Token); err != nil {
+		return nil, statusError(err, req.Name)
 	}
 	return &pb.Empty{}, nil
 }
+
+// Watch streams the changes of a key or the keys with a prefix until the client
+// cancels the call. A client that doesn't keep up with the changes is disconnected
+// with the ResourceExhausted status.
+func (c *cacheServer) Watch(req *pb.WatchRequest, stream pb.Storage_WatchServer) error {
+	cache, err := c.cacheFor(req.Namespace)
+	if err != nil {
+		return err
+	}
+	sub := cache.Subscribe(kv.SubscribeOptions{Key: req.Key, Prefix: req.Prefix})
+	defer sub.Close()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case ev, ok := <-sub.C:
+			if !ok {
+				if sub.Err() != nil {
+					return status.Error(codes.ResourceExhausted, sub.Err().Error())
+				}
+				return status.Error(codes.Unavailable, "cache is closed")
+			}
+			if err := stream.Send(toPbEvent(ev)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// optionalTtl converts the TTL of a request. Nil means no TTL and is returned as zero.
+func optionalTtl(ttl *duration.Duration) (time.Duration, error) {
+	if ttl == nil {
+		return 0, nil
+	}
+	return requiredTtl(ttl)
+}
+
+// requiredTtl converts the TTL of a request, it must be positive and not longer than maxTtl.
+func requiredTtl(ttl *duration.Duration) (time.Duration, error) {
+	dur, err := ptypes.Duration(ttl)
+	if err != nil || dur <= 0 || dur > maxTtl {
+		return 0, kv.ErrInvalidTtl
+	}
+	return dur, nil
+}
+
+// fromPb converts the value of a request into the cache value.
+func fromPb(t *pb.T) kv.T {
+	return kv.T{V: t.GetValue(), ContentType: t.GetContentType()}
+}
+
+// toPb converts the cache value into the value of a response.
+func toPb(t kv.T) *pb.T {
+	return &pb.T{Value: t.V, ContentType: t.ContentType}
+}
+
+// toPbItem converts the cache pair into the item of a response.
+func toPbItem(item kv.Item) *pb.Item {
+	value := toPb(item.Content)
+	value.Version = item.Version
+	created, _ := ptypes.TimestampProto(item.CreatedAt)
+	pi := &pb.Item{Key: item.Key, Value: value, CreatedAt: created}
+	if item.Expired != nil {
+		pi.Expired, _ = ptypes.TimestampProto(*item.Expired)
+	}
+	return pi
+}
+
+// toPbEvent converts the cache event into the message of the Watch stream.
+func toPbEvent(ev kv.Event) *pb.Event {
+	value := toPb(ev.Content)
+	value.Version = ev.Version
+	pe := &pb.Event{Type: eventTypes[ev.Kind], Key: ev.Key, Value: value}
+	if ev.Expired != nil {
+		pe.Expired, _ = ptypes.TimestampProto(*ev.Expired)
+	}
+	return pe
+}
+
+// statusError converts an error of the kv package into a gRPC status error
+// with the matching code. The key is attached as ResourceInfo details,
+// the reason distinguishing errors with the same code - as ErrorInfo details.
+func statusError(err error, key string) error {
+	return resourceError(err, pb.ResourceType, key)
+}
+
+// namespaceError is like statusError for errors related to a namespace.
+func namespaceError(err error, namespace string) error {
+	return resourceError(err, pb.NamespaceResourceType, namespace)
+}
+
+func resourceError(err error, resourceType, name string) error {
+	code, reason := codes.Internal, ""
+	for _, e := range kvErrors {
+		if errors.Is(err, e.err) {
+			code, reason = e.code, e.reason
+			break
+		}
+	}
+	st := status.Newf(code, "%s: %q", err, name)
+	details := []proto.Message{&errdetails.ResourceInfo{
+		ResourceType: resourceType,
+		ResourceName: name,
+	}}
+	if reason != "" {
+		details = append(details, &errdetails.ErrorInfo{Reason: reason, Domain: pb.ErrorDomain})
+	}
+	detailed, dErr := st.WithDetails(details...)
+	if dErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
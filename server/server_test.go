@@ -0,0 +1,353 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"kv-ttl/kv"
+	"kv-ttl/pb"
+	"testing"
+	"time"
+)
+
+func TestErrorCodes(t *testing.T) {
+	cache := kv.NewCache(kv.Configuration{})
+	defer cache.Close(context.Background())
+	srv := NewCacheServer(cache)
+	ctx := context.Background()
+
+	_, err := srv.Value(ctx, &pb.Key{Key: "missing"})
+	assertStatus(t, err, codes.NotFound, "missing")
+
+	_, err = srv.TimeAlive(ctx, &pb.Key{Key: "missing"})
+	assertStatus(t, err, codes.NotFound, "missing")
+
+	_, err = srv.SetTtl(ctx, &pb.TtlRequest{Key: "missing", Stamp: ptypes.TimestampNow()})
+	assertStatus(t, err, codes.NotFound, "missing")
+
+	_, err = srv.SetTtl(ctx, &pb.TtlRequest{Key: "key"})
+	assertStatus(t, err, codes.InvalidArgument, "key")
+
+	_, err = srv.AddWithTtl(ctx, &pb.KeyValueTtl{Key: "key", Value: &pb.T{Value: []byte("v")}, Ttl: ptypes.DurationProto(-1)})
+	assertStatus(t, err, codes.InvalidArgument, "key")
+
+	_, err = srv.Expire(ctx, &pb.ExpireRequest{Key: "missing", Ttl: ptypes.DurationProto(time.Minute)})
+	assertStatus(t, err, codes.NotFound, "missing")
+
+	_, err = srv.Persist(ctx, &pb.Key{Key: "missing"})
+	assertStatus(t, err, codes.NotFound, "missing")
+
+	srv.Add(ctx, &pb.KeyValue{Key: "name", Value: &pb.T{Value: []byte("john")}})
+	_, err = srv.Incr(ctx, &pb.IncrRequest{Key: "name", Delta: 1})
+	assertStatus(t, err, codes.FailedPrecondition, "name")
+	_, err = srv.CompareAndSwap(ctx, &pb.CasRequest{Key: "name", Value: &pb.T{Value: []byte("jack")}})
+	assertStatus(t, err, codes.AlreadyExists, "name")
+
+	_, err = srv.Add(ctx, &pb.KeyValue{Key: "", Value: &pb.T{Value: []byte("v")}})
+	assertStatus(t, err, codes.InvalidArgument, "")
+
+	_, err = srv.MultiSet(ctx, &pb.MultiSetRequest{Items: []*pb.SetRequest{
+		{Key: "first", Value: &pb.T{Value: []byte("v")}},
+		{Key: "", Value: &pb.T{Value: []byte("v")}},
+	}})
+	assertStatus(t, err, codes.InvalidArgument, "")
+	if _, ok := cache.Value("first"); ok {
+		t.Error("expected the valid item of the rejected batch not to be written")
+	}
+
+	if resp, err := srv.Remove(ctx, &pb.Key{Key: "missing"}); resp == nil || err != nil {
+		t.Errorf("expected empty response, got %v %v", resp, err)
+	}
+}
+
+func TestExpirePersist(t *testing.T) {
+	cache := kv.NewCache(kv.Configuration{})
+	defer cache.Close(context.Background())
+	srv := NewCacheServer(cache)
+	ctx := context.Background()
+	srv.Add(ctx, &pb.KeyValue{Key: "key", Value: &pb.T{Value: []byte("v")}})
+
+	for _, ttl := range []time.Duration{-time.Second, 0, 100 * 365 * 24 * time.Hour} {
+		_, err := srv.Expire(ctx, &pb.ExpireRequest{Key: "key", Ttl: ptypes.DurationProto(ttl)})
+		assertStatus(t, err, codes.InvalidArgument, "key")
+	}
+	_, err := srv.Expire(ctx, &pb.ExpireRequest{Key: "key"})
+	assertStatus(t, err, codes.InvalidArgument, "key")
+
+	if _, err := srv.Expire(ctx, &pb.ExpireRequest{Key: "key", Ttl: ptypes.DurationProto(time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	if box, _ := cache.Entry("key"); box.Expired == nil {
+		t.Error("expected expiration date to be set")
+	}
+	if _, err := srv.Persist(ctx, &pb.Key{Key: "key"}); err != nil {
+		t.Fatal(err)
+	}
+	if box, _ := cache.Entry("key"); box.Expired != nil {
+		t.Errorf("expected expiration date to be removed, got %v", box.Expired)
+	}
+}
+
+func TestTtl(t *testing.T) {
+	clock := kv.NewFakeClock(time.Now())
+	cache := kv.NewCache(kv.Configuration{Clock: clock})
+	defer cache.Close(context.Background())
+	srv := NewCacheServer(cache)
+	ctx := context.Background()
+
+	_, err := srv.Ttl(ctx, &pb.Key{Key: "missing"})
+	assertStatus(t, err, codes.NotFound, "missing")
+
+	cache.Add("permanent", kv.T{V: []byte("v")})
+	if info, err := srv.Ttl(ctx, &pb.Key{Key: "permanent"}); err != nil || !info.NoExpiry || info.Remaining != nil {
+		t.Errorf("expected no expiry, got %v %v", info, err)
+	}
+	cache.AddWithTtl("temporary", kv.T{V: []byte("v")}, time.Minute)
+	clock.Advance(20 * time.Second)
+	info, err := srv.Ttl(ctx, &pb.Key{Key: "temporary"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	remaining, _ := ptypes.Duration(info.Remaining)
+	expired, _ := ptypes.Timestamp(info.Expired)
+	if info.NoExpiry || remaining != 40*time.Second || !expired.Equal(clock.Now().Add(40*time.Second)) {
+		t.Errorf("expected 40s left, got %v", info)
+	}
+	if age, _ := cache.TimeAlive("temporary"); age != 20*time.Second {
+		t.Errorf("expected age of 20s, got %v", age)
+	}
+}
+
+func TestSetModes(t *testing.T) {
+	cache := kv.NewCache(kv.Configuration{})
+	defer cache.Close(context.Background())
+	srv := NewCacheServer(cache)
+	ctx := context.Background()
+
+	steps := []struct {
+		value   string
+		mode    pb.WriteMode
+		written bool
+	}{
+		{"a", pb.WriteMode_IF_PRESENT, false},
+		{"b", pb.WriteMode_IF_ABSENT, true},
+		{"c", pb.WriteMode_IF_ABSENT, false},
+		{"d", pb.WriteMode_IF_PRESENT, true},
+		{"e", pb.WriteMode_ALWAYS, true},
+	}
+	for _, step := range steps {
+		resp, err := srv.Set(ctx, &pb.SetRequest{Key: "key", Value: &pb.T{Value: []byte(step.value)}, Mode: step.mode})
+		if err != nil || resp.Written != step.written {
+			t.Errorf("expected %v to be written: %v, got %v %v", step.mode, step.written, resp, err)
+		}
+	}
+	if v, _ := cache.Value("key"); string(v.V) != "e" {
+		t.Errorf("expected the last written value, got %s", v.V)
+	}
+
+	_, err := srv.Set(ctx, &pb.SetRequest{Key: "key", Value: &pb.T{Value: []byte("f")}, Mode: pb.WriteMode(42)})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected unknown mode to be rejected, got %v", err)
+	}
+	if v, _ := cache.Value("key"); string(v.V) != "e" {
+		t.Errorf("expected the value to be kept, got %s", v.V)
+	}
+}
+
+func TestNamespaces(t *testing.T) {
+	cache := kv.NewCache(kv.Configuration{})
+	srv := NewCacheServer(cache)
+	defer srv.(*cacheServer).namespaces.Close(context.Background())
+	ctx := context.Background()
+
+	_, err := srv.Value(ctx, &pb.Key{Key: "key", Namespace: "team"})
+	assertResource(t, err, codes.NotFound, pb.NamespaceResourceType, "team")
+
+	if _, err := srv.CreateNamespace(ctx, &pb.NamespaceInfo{Name: "team"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = srv.CreateNamespace(ctx, &pb.NamespaceInfo{Name: "team"})
+	assertResource(t, err, codes.AlreadyExists, pb.NamespaceResourceType, "team")
+	_, err = srv.CreateNamespace(ctx, &pb.NamespaceInfo{Name: "bad name"})
+	assertResource(t, err, codes.InvalidArgument, pb.NamespaceResourceType, "bad name")
+
+	srv.Add(ctx, &pb.KeyValue{Key: "key", Value: &pb.T{Value: []byte("team")}, Namespace: "team"})
+	_, err = srv.Value(ctx, &pb.Key{Key: "key"})
+	assertStatus(t, err, codes.NotFound, "key")
+	if v, err := srv.Value(ctx, &pb.Key{Key: "key", Namespace: "team"}); err != nil || string(v.Value) != "team" {
+		t.Errorf("expected value of the namespace, got %v %v", v, err)
+	}
+
+	list, _ := srv.ListNamespaces(ctx, &pb.Empty{})
+	if len(list.Namespaces) != 2 || list.Namespaces[1].Name != "team" {
+		t.Errorf("unexpected namespaces %v", list.Namespaces)
+	}
+	if _, err := srv.DropNamespace(ctx, &pb.Namespace{Namespace: "team"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = srv.DropNamespace(ctx, &pb.Namespace{Namespace: "team"})
+	assertResource(t, err, codes.NotFound, pb.NamespaceResourceType, "team")
+}
+
+func TestStats(t *testing.T) {
+	cache := kv.NewCache(kv.Configuration{})
+	srv := NewCacheServer(cache)
+	defer srv.(*cacheServer).namespaces.Close(context.Background())
+	ctx := context.Background()
+
+	if _, err := srv.CreateNamespace(ctx, &pb.NamespaceInfo{Name: "small", MaxEntries: 1}); err != nil {
+		t.Fatal(err)
+	}
+	srv.Add(ctx, &pb.KeyValue{Key: "a", Value: &pb.T{Value: []byte("v")}, Namespace: "small"})
+	srv.Add(ctx, &pb.KeyValue{Key: "b", Value: &pb.T{Value: []byte("v")}, Namespace: "small"})
+	if stats, err := srv.Stats(ctx, &pb.Namespace{Namespace: "small"}); err != nil || stats.Evictions != 1 || stats.Expirations != 0 {
+		t.Errorf("expected a single eviction, got %v %v", stats, err)
+	}
+	if stats, err := srv.Stats(ctx, &pb.Namespace{}); err != nil || stats.Evictions != 0 {
+		t.Errorf("expected no evictions in the default namespace, got %v %v", stats, err)
+	}
+	_, err := srv.Stats(ctx, &pb.Namespace{Namespace: "team"})
+	assertResource(t, err, codes.NotFound, pb.NamespaceResourceType, "team")
+}
+
+func TestTxn(t *testing.T) {
+	cache := kv.NewCache(kv.Configuration{})
+	defer cache.Close(context.Background())
+	srv := NewCacheServer(cache)
+	ctx := context.Background()
+	cache.Add("user:1", kv.T{V: []byte("john")})
+	box, _ := cache.Entry("user:1")
+
+	req := &pb.TxnRequest{
+		Checks: []*pb.TxnCheck{
+			{Key: "user:1", Version: box.Version},
+			{Key: "name:jack", Kind: pb.TxnCheck_ABSENT},
+		},
+		Ops: []*pb.TxnOp{
+			{Key: "user:1", Value: &pb.T{Value: []byte("jack")}},
+			{Key: "name:jack", Value: &pb.T{Value: []byte("1")}},
+			{Key: "name:john", Kind: pb.TxnOp_DELETE},
+		},
+	}
+	if resp, err := srv.Txn(ctx, req); err != nil || !resp.Committed {
+		t.Fatalf("expected transaction to commit, got %v %v", resp, err)
+	}
+	if v, _ := cache.Value("user:1"); string(v.V) != "jack" {
+		t.Errorf("expected ops to be applied, got %s", v.V)
+	}
+	resp, err := srv.Txn(ctx, req)
+	if err != nil || resp.Committed || resp.FailedCheck != 0 {
+		t.Errorf("expected the version check to fail, got %v %v", resp, err)
+	}
+
+	_, err = srv.Txn(ctx, &pb.TxnRequest{Ops: []*pb.TxnOp{{Key: "k", Ttl: ptypes.DurationProto(-time.Second)}}})
+	assertStatus(t, err, codes.InvalidArgument, "k")
+}
+
+func TestWatchSlowSubscriber(t *testing.T) {
+	cache := kv.NewCache(kv.Configuration{})
+	defer cache.Close(context.Background())
+	_, client, stop := serve(t, NewCacheServer(cache))
+	defer stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &pb.WatchRequest{Key: "key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The server subscribes in the background, the value is written until the first event arrives.
+	subscribed := make(chan struct{})
+	go func() {
+		for {
+			cache.Add("key", kv.T{V: []byte("subscribed")})
+			select {
+			case <-subscribed:
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}()
+	_, err = stream.Recv()
+	close(subscribed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The client doesn't read, so the transport and then the subscription buffer fill up.
+	value := kv.T{V: make([]byte, 64*1024)}
+	for i := 0; i < 4*kv.DefaultEventBuffer; i++ {
+		cache.Add("key", value)
+	}
+	for {
+		_, err = stream.Recv()
+		if err != nil {
+			break
+		}
+	}
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted || st.Message() != kv.ErrSlowSubscriber.Error() {
+		t.Errorf("expected the slow subscriber to be dropped, got %v", err)
+	}
+}
+
+// failingLog is a log storage refusing every change.
+type failingLog struct {
+	kv.UnimplementedStorage
+}
+
+func (*failingLog) Append(changes ...kv.Change) error {
+	return errors.New("disk is full")
+}
+
+func (*failingLog) Sync() error   { return nil }
+func (*failingLog) Rotate() error { return nil }
+
+func TestStorageUnavailable(t *testing.T) {
+	cache := kv.NewCache(kv.Configuration{Storage: &failingLog{}})
+	defer cache.Close(context.Background())
+	srv := NewCacheServer(cache)
+	ctx := context.Background()
+
+	_, err := srv.Add(ctx, &pb.KeyValue{Key: "key", Value: &pb.T{Value: []byte("v")}})
+	assertStatus(t, err, codes.Unavailable, "key")
+	reason := ""
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			reason = info.Reason
+		}
+	}
+	if reason != pb.ReasonUnavailable {
+		t.Errorf("expected %s reason, got %q", pb.ReasonUnavailable, reason)
+	}
+	if resp, err := srv.Set(ctx, &pb.SetRequest{Key: "key", Value: &pb.T{Value: []byte("v")}}); err == nil {
+		t.Errorf("expected set to fail, got %v", resp)
+	}
+	_, err = srv.MultiSet(ctx, &pb.MultiSetRequest{Items: []*pb.SetRequest{{Key: "key", Value: &pb.T{}}}})
+	assertResource(t, err, codes.Unavailable, pb.NamespaceResourceType, "")
+}
+
+func assertStatus(t *testing.T, err error, code codes.Code, key string) {
+	t.Helper()
+	assertResource(t, err, code, pb.ResourceType, key)
+}
+
+func assertResource(t *testing.T, err error, code codes.Code, resourceType, name string) {
+	t.Helper()
+	st := status.Convert(err)
+	if st.Code() != code {
+		t.Errorf("expected %v, got %v", code, err)
+		return
+	}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ResourceInfo); ok {
+			if info.ResourceType != resourceType || info.ResourceName != name {
+				t.Errorf("expected %s %q in details, got %v", resourceType, name, info)
+			}
+			return
+		}
+	}
+	t.Errorf("expected %s details, got %v", resourceType, st.Details())
+}